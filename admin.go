@@ -0,0 +1,199 @@
+package msa
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/go-god/msa/config"
+	"github.com/go-god/msa/logger"
+	"github.com/go-god/msa/metrics"
+)
+
+// AdminSensitiveKeys the config values whose key contains one of these words
+// will be masked by the admin server
+var AdminSensitiveKeys = []string{
+	"password", "passwd", "pwd", "secret", "token", "api_key", "apikey",
+	"private_key", "access_key", "credential", "dsn",
+}
+
+// adminMask mask value of sensitive config
+const adminMask = "******"
+
+// adminHandler admin http handler
+/**
+/healthz       liveness
+/readyz        readiness
+/state         engine lifecycle state
+/config        loaded config,sensitive values are masked
+/config/reload POST to reload config file
+/metrics       prometheus metrics
+/objects       registered inject objects
+/graph         dependency graph,?format=dot for graphviz
+/buildinfo     go version and module build info
+/loglevel      GET current log levels,PUT or POST ?level=debug to change it,
+               ?module=grpc&level=warn to change a module,an empty level removes the module
+/debug/pprof/  pprof
+*/
+func (e *Engine) adminHandler() http.Handler {
+	mux := http.NewServeMux()
+	e.health.Mount(mux)
+	mux.HandleFunc("/state", e.adminState)
+	mux.HandleFunc("/config", e.adminConfig)
+	mux.HandleFunc("/config/reload", e.adminConfigReload)
+	mux.Handle("/metrics", metrics.DefaultRegistry.Handler())
+	mux.HandleFunc("/objects", e.adminObjects)
+	mux.HandleFunc("/graph", e.adminGraph)
+	mux.HandleFunc("/buildinfo", adminBuildInfo)
+	mux.HandleFunc("/loglevel", adminLogLevel)
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
+
+func (e *Engine) adminState(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"state": e.State().String(),
+		"ready": e.health.IsReady(),
+	})
+}
+
+func (e *Engine) adminConfig(w http.ResponseWriter, r *http.Request) {
+	reader, ok := e.configInterface.(config.SettingsReader)
+	if !ok {
+		writeJSON(w, http.StatusNotImplemented, map[string]string{
+			"error": "config interface does not support reading all settings",
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, maskSettings(reader.AllSettings()))
+}
+
+func (e *Engine) adminConfigReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	if err := e.ReloadConf(); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"result": "success"})
+}
+
+func (e *Engine) adminObjects(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, e.Objects())
+}
+
+func (e *Engine) adminGraph(w http.ResponseWriter, r *http.Request) {
+	g := e.Graph()
+	if r.URL.Query().Get("format") == "dot" {
+		w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		_, _ = w.Write([]byte(g.DOT()))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, g)
+}
+
+func adminBuildInfo(w http.ResponseWriter, r *http.Request) {
+	info := map[string]interface{}{
+		"go_version": runtime.Version(),
+		"goos":       runtime.GOOS,
+		"goarch":     runtime.GOARCH,
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info["path"] = bi.Path
+		info["main"] = bi.Main
+		info["deps"] = bi.Deps
+	}
+
+	writeJSON(w, http.StatusOK, info)
+}
+
+func adminLogLevel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		var err error
+		if module := r.FormValue("module"); module != "" {
+			err = logger.SetModuleLevel(module, r.FormValue("level"))
+		} else {
+			err = logger.SetLevel(r.FormValue("level"))
+		}
+
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"level":   logger.GetLevel(),
+		"modules": logger.GetModuleLevels(),
+	})
+}
+
+// maskSettings return a copy of settings whose sensitive values are masked
+func maskSettings(settings map[string]interface{}) map[string]interface{} {
+	masked := make(map[string]interface{}, len(settings))
+	for k, v := range settings {
+		if isSensitiveKey(k) {
+			masked[k] = adminMask
+			continue
+		}
+
+		masked[k] = maskValue(v)
+	}
+
+	return masked
+}
+
+// maskValue mask nested maps and slices
+func maskValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return maskSettings(val)
+	case []interface{}:
+		values := make([]interface{}, len(val))
+		for i := range val {
+			values[i] = maskValue(val[i])
+		}
+
+		return values
+	}
+
+	return v
+}
+
+// isSensitiveKey check key contains one of AdminSensitiveKeys
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range AdminSensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writeJSON write v as json response
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,33 @@
+package msa
+
+import (
+	"testing"
+)
+
+// TestMaskSettings test sensitive config values are masked.
+func TestMaskSettings(t *testing.T) {
+	masked := maskSettings(map[string]interface{}{
+		"app_name": "demo",
+		"mysql": map[string]interface{}{
+			"user":     "root",
+			"password": "123456",
+		},
+		"clients": []interface{}{
+			map[string]interface{}{"access_token": "abc"},
+		},
+	})
+
+	if masked["app_name"] != "demo" {
+		t.Fatalf("app_name: %v", masked["app_name"])
+	}
+
+	mysql := masked["mysql"].(map[string]interface{})
+	if mysql["user"] != "root" || mysql["password"] != adminMask {
+		t.Fatalf("mysql: %v", mysql)
+	}
+
+	client := masked["clients"].([]interface{})[0].(map[string]interface{})
+	if client["access_token"] != adminMask {
+		t.Fatalf("client: %v", client)
+	}
+}
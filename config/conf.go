@@ -9,3 +9,16 @@ type ConfigInterface interface {
 	// GetValue get key to obj,obj must be a pointer
 	GetValue(key string, obj interface{}) error
 }
+
+// SettingsReader optional interface for ConfigInterface to read all settings
+type SettingsReader interface {
+	// AllSettings return all settings as a nested map
+	AllSettings() map[string]interface{}
+}
+
+// Reloader optional interface for ConfigInterface to reload config file
+type Reloader interface {
+	// Reload read config file again,the new values are returned by later GetValue calls.
+	// It must be safe to call concurrently with IsSet and GetValue.
+	Reload() error
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"sync"
 
 	"github.com/go-god/setting"
 )
@@ -30,8 +31,12 @@ func New(opts ...Option) ConfigInterface {
 	return c
 }
 
+// configImpl config interface implementation,
+// setting.Setting is not safe for concurrent use,so it is guarded by mu.
 type configImpl struct {
-	s *setting.Setting
+	mu   sync.RWMutex
+	s    *setting.Setting
+	conf ConfigOption
 }
 
 // Load load config
@@ -49,21 +54,54 @@ func (c *configImpl) Load(opts ...Option) error {
 		o(conf)
 	}
 
-	var err error
-	c.s, err = setting.NewSetting(conf.configDir, conf.configFile)
+	s, err := setting.NewSetting(conf.configDir, conf.configFile)
 	if err != nil {
 		return fmt.Errorf("init config error: " + err.Error())
 	}
 
+	c.mu.Lock()
+	c.s = s
+	c.conf = *conf
+	c.mu.Unlock()
 	return nil
 }
 
 // IsSet is set value
 func (c *configImpl) IsSet(key string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.s.IsSet(key)
 }
 
 // GetValue get key to obj,obj must be a pointer
 func (c *configImpl) GetValue(key string, obj interface{}) error {
+	// ReadSection writes the section map of setting
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.s.ReadSection(key, obj)
 }
+
+// AllSettings return all settings as a nested map
+func (c *configImpl) AllSettings() map[string]interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.s.GetVp().AllSettings()
+}
+
+// Reload read config file into a new setting and swap it in,
+// the objects which have been read are not changed,read them again by GetValue.
+func (c *configImpl) Reload() error {
+	c.mu.RLock()
+	conf := c.conf
+	c.mu.RUnlock()
+
+	s, err := setting.NewSetting(conf.configDir, conf.configFile)
+	if err != nil {
+		return fmt.Errorf("reload config error: %w", err)
+	}
+
+	c.mu.Lock()
+	c.s = s
+	c.mu.Unlock()
+	return nil
+}
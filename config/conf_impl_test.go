@@ -0,0 +1,57 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// TestReload test reload swaps the config while sections are read concurrently.
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.yaml")
+	if err := os.WriteFile(file, []byte("app:\n  name: v1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New(WithConfigDir(dir), WithConfigFile("app.yaml"))
+	var app struct {
+		Name string
+	}
+	if err := c.GetValue("app", &app); err != nil || app.Name != "v1" {
+		t.Fatalf("app: %+v error: %v", app, err)
+	}
+
+	if err := os.WriteFile(file, []byte("app:\n  name: v2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			var v struct {
+				Name string
+			}
+			c.GetValue("app", &v)
+			c.IsSet("app")
+		}()
+		go func() {
+			defer wg.Done()
+			if err := c.(Reloader).Reload(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if app.Name != "v1" {
+		t.Fatalf("loaded object changed by reload: %+v", app)
+	}
+
+	if err := c.GetValue("app", &app); err != nil || app.Name != "v2" {
+		t.Fatalf("app after reload: %+v error: %v", app, err)
+	}
+}
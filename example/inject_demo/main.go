@@ -49,6 +49,10 @@ func main() {
 			Value: &Service{},
 		}),
 		msa.WithConfigInterface(config.New(config.WithConfigFile("test.yaml"))),
+		msa.WithInvoke(func(c config.ConfigInterface, a *App) error {
+			log.Println("invoke app service is set: ", c.IsSet("service") && a.Service != nil)
+			return nil
+		}),
 	}
 
 	// the first way
@@ -0,0 +1,220 @@
+package msa
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// injectTag struct field tag used by injector
+const injectTag = "inject"
+
+// Graph dependency graph of inject objects
+type Graph struct {
+	Nodes   []GraphNode `json:"nodes"`
+	Edges   []GraphEdge `json:"edges"`
+	Missing []GraphEdge `json:"missing,omitempty"` // dependencies which can not be satisfied
+	Unused  []string    `json:"unused,omitempty"`  // node ids which are not used by others
+}
+
+// GraphNode inject object node
+type GraphNode struct {
+	ID string `json:"id"`
+	ObjectInfo
+}
+
+// GraphEdge From depends on To by the struct Field
+// To is the node id,for a missing dependency it is the required type or name
+type GraphEdge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Field string `json:"field"`
+}
+
+// Graph build dependency graph by the inject tag of struct fields.
+// The dependency is matched by name for `inject:"name"`,
+// otherwise it is matched by type for `inject:""`.
+// Named dependencies and interface fields which are not registered are reported as missing,
+// other unregistered types are created by the injector.
+// Objects which are not depended on and are not Init/Start/Stop components are reported as unused.
+func (e *Engine) Graph() *Graph {
+	objects := e.Objects()
+	g := &Graph{
+		Nodes: make([]GraphNode, 0, len(objects)),
+		Edges: make([]GraphEdge, 0, len(objects)),
+	}
+
+	ids := make(map[string]int, len(objects))
+	for _, obj := range objects {
+		id := obj.Name
+		if id == "" {
+			id = obj.Type
+		}
+
+		if n := ids[id]; n > 0 {
+			ids[id] = n + 1
+			id = id + "#" + strconv.Itoa(n+1)
+		} else {
+			ids[id] = 1
+		}
+
+		g.Nodes = append(g.Nodes, GraphNode{ID: id, ObjectInfo: obj})
+	}
+
+	used := make(map[string]bool, len(g.Nodes))
+	for _, node := range g.Nodes {
+		for _, dep := range injectFields(node.Value) {
+			edge := GraphEdge{From: node.ID, Field: dep.field}
+			if to, ok := g.findNode(dep); ok {
+				edge.To = to
+				used[to] = true
+				g.Edges = append(g.Edges, edge)
+				continue
+			}
+
+			if !dep.required() {
+				continue
+			}
+
+			if dep.name != "" {
+				edge.To = dep.name
+			} else {
+				edge.To = dep.typ.String()
+			}
+
+			g.Missing = append(g.Missing, edge)
+		}
+	}
+
+	for _, node := range g.Nodes {
+		if used[node.ID] || isComponent(node.Value) {
+			continue
+		}
+
+		g.Unused = append(g.Unused, node.ID)
+	}
+
+	return g
+}
+
+// JSON return graph json format
+func (g *Graph) JSON() ([]byte, error) {
+	return json.MarshalIndent(g, "", "  ")
+}
+
+// DOT return graph graphviz dot format,
+// missing dependencies are drawn with dashed red edges
+// and unused objects are drawn with gray nodes.
+func (g *Graph) DOT() string {
+	unused := make(map[string]bool, len(g.Unused))
+	for _, id := range g.Unused {
+		unused[id] = true
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("digraph msa {\n")
+	buf.WriteString("  node [shape=box];\n")
+	for _, node := range g.Nodes {
+		label := node.Type
+		if node.Name != "" {
+			label = node.Name + "\\n" + label
+		}
+
+		if node.Group != "" {
+			label += "\\ngroup: " + node.Group
+		}
+
+		label += "\\n(" + string(node.Source) + ")"
+		attrs := "label=" + dotQuote(label)
+		if unused[node.ID] {
+			attrs += ", color=gray, fontcolor=gray"
+		}
+
+		fmt.Fprintf(&buf, "  %s [%s];\n", dotQuote(node.ID), attrs)
+	}
+
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&buf, "  %s -> %s [label=%s];\n", dotQuote(edge.From), dotQuote(edge.To), dotQuote(edge.Field))
+	}
+
+	for _, edge := range g.Missing {
+		fmt.Fprintf(&buf, "  %s [color=red, style=dashed];\n", dotQuote(edge.To))
+		fmt.Fprintf(&buf, "  %s -> %s [label=%s, color=red, style=dashed];\n",
+			dotQuote(edge.From), dotQuote(edge.To), dotQuote(edge.Field))
+	}
+
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+// findNode find the node which satisfies dependency
+func (g *Graph) findNode(dep injectDep) (string, bool) {
+	for _, node := range g.Nodes {
+		if dep.name != "" {
+			if node.Name == dep.name {
+				return node.ID, true
+			}
+
+			continue
+		}
+
+		if node.Value != nil && reflect.TypeOf(node.Value).AssignableTo(dep.typ) {
+			return node.ID, true
+		}
+	}
+
+	return "", false
+}
+
+// injectDep dependency declared by struct field inject tag
+type injectDep struct {
+	field string
+	name  string
+	typ   reflect.Type
+}
+
+// required check the dependency must be registered,
+// the injector creates the unregistered dependency which is not named or an interface
+func (d injectDep) required() bool {
+	return d.name != "" || d.typ.Kind() == reflect.Interface
+}
+
+// injectFields return the dependencies of a pointer to struct value
+func injectFields(value interface{}) []injectDep {
+	t := reflect.TypeOf(value)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	t = t.Elem()
+	deps := make([]injectDep, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup(injectTag)
+		if !ok || tag == "private" || tag == "inline" {
+			continue
+		}
+
+		deps = append(deps, injectDep{field: field.Name, name: tag, typ: field.Type})
+	}
+
+	return deps
+}
+
+// isComponent check value has Init, Start or Stop action
+func isComponent(value interface{}) bool {
+	switch value.(type) {
+	case initializer, starter, stoppable:
+		return true
+	}
+
+	return false
+}
+
+// dotQuote quote id or label for dot format
+func dotQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
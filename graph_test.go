@@ -0,0 +1,71 @@
+package msa
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-god/gdi"
+)
+
+type graphRepo struct{}
+
+type graphCache struct{}
+
+type graphAuto struct{}
+
+type graphStore interface {
+	Get(key string) string
+}
+
+type graphService struct {
+	Repo  *graphRepo  `inject:""`
+	Cache *graphCache `inject:"cache"`
+}
+
+func (s *graphService) Start() error {
+	return nil
+}
+
+type graphHandler struct {
+	Auto  *graphAuto `inject:""`
+	Store graphStore `inject:""`
+}
+
+func (h *graphHandler) Stop() {}
+
+// TestEngineGraph test objects and dependency graph.
+func TestEngineGraph(t *testing.T) {
+	e := &Engine{}
+	WithInjectValues(
+		&gdi.Object{Value: &graphService{}},
+		&gdi.Object{Value: &graphRepo{}},
+		&gdi.Object{Value: &struct{}{}, Name: "unused", Group: "misc"},
+		&gdi.Object{Value: &graphHandler{}},
+	)(e)
+
+	objects := e.Objects()
+	if len(objects) != 4 || objects[0].Type != "*msa.graphService" || objects[0].Source != SourceOption {
+		t.Fatalf("objects: %+v", objects)
+	}
+
+	g := e.Graph()
+	if len(g.Edges) != 1 || g.Edges[0].From != "*msa.graphService" || g.Edges[0].To != "*msa.graphRepo" {
+		t.Fatalf("edges: %+v", g.Edges)
+	}
+
+	if len(g.Missing) != 2 || g.Missing[0].To != "cache" || g.Missing[1].To != "msa.graphStore" {
+		t.Fatalf("missing: %+v", g.Missing)
+	}
+
+	if len(g.Unused) != 1 || g.Unused[0] != "unused" {
+		t.Fatalf("unused: %+v", g.Unused)
+	}
+
+	if dot := g.DOT(); !strings.Contains(dot, `"*msa.graphService" -> "*msa.graphRepo" [label="Repo"];`) {
+		t.Fatalf("dot: %s", dot)
+	}
+
+	if _, err := g.JSON(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,57 @@
+package grpcserver
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultServiceGroup default inject group of grpc services
+var DefaultServiceGroup = "grpc_service"
+
+// Config grpc server config
+/**
+grpc_server:
+  addr: ":50051"
+  service_group: grpc_service
+  max_recv_msg_size: 4194304
+  max_send_msg_size: 4194304
+  connection_timeout: 120s
+  shutdown_timeout: 5s
+  cert_file: ""
+  key_file: ""
+*/
+type Config struct {
+	Addr              string        `json:"addr" mapstructure:"addr"`
+	ServiceGroup      string        `json:"service_group" mapstructure:"service_group"`
+	MaxRecvMsgSize    int           `json:"max_recv_msg_size" mapstructure:"max_recv_msg_size"`
+	MaxSendMsgSize    int           `json:"max_send_msg_size" mapstructure:"max_send_msg_size"`
+	ConnectionTimeout time.Duration `json:"connection_timeout" mapstructure:"connection_timeout"`
+	ShutdownTimeout   time.Duration `json:"shutdown_timeout" mapstructure:"shutdown_timeout"`
+	CertFile          string        `json:"cert_file" mapstructure:"cert_file"`
+	KeyFile           string        `json:"key_file" mapstructure:"key_file"`
+}
+
+// DefaultConfig return default grpc server config
+func DefaultConfig() Config {
+	return Config{
+		Addr:              ":50051",
+		ServiceGroup:      DefaultServiceGroup,
+		MaxRecvMsgSize:    4 << 20,
+		MaxSendMsgSize:    4 << 20,
+		ConnectionTimeout: 120 * time.Second,
+		ShutdownTimeout:   5 * time.Second,
+	}
+}
+
+// Validate check config
+func (c *Config) Validate() error {
+	if c.Addr == "" {
+		return errors.New("grpc server addr is empty")
+	}
+
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return errors.New("grpc server cert_file and key_file must be set together")
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package grpcserver
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/go-god/msa/health"
+)
+
+// HealthWatchInterval interval of health Watch polling the engine health state,
+// the readiness report is shared by all Watch streams within the interval.
+var HealthWatchInterval = time.Second
+
+// healthServer standard grpc health service,
+// the empty service name reports the engine readiness,
+// other service names report the readiness check of the same name.
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	s *Server
+
+	mu        sync.Mutex
+	report    health.Report // last readiness report of Watch
+	checkedAt time.Time     // time of the last readiness report
+}
+
+// Check return serving status of service
+func (h *healthServer) Check(ctx context.Context,
+	req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	st, ok := h.status(req.GetService(), func() health.Report {
+		return h.s.engine.Health().Readiness(ctx)
+	})
+	if !ok {
+		return nil, status.Error(codes.NotFound, "unknown service")
+	}
+
+	return &grpc_health_v1.HealthCheckResponse{Status: st}, nil
+}
+
+// Watch send serving status of service whenever it changes
+func (h *healthServer) Watch(req *grpc_health_v1.HealthCheckRequest,
+	stream grpc_health_v1.Health_WatchServer) error {
+	ticker := time.NewTicker(HealthWatchInterval)
+	defer ticker.Stop()
+
+	last := grpc_health_v1.HealthCheckResponse_ServingStatus(-1)
+	for {
+		st, ok := h.status(req.GetService(), h.sharedReadiness)
+		if !ok {
+			st = grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN
+		}
+
+		if st != last {
+			if err := stream.Send(&grpc_health_v1.HealthCheckResponse{Status: st}); err != nil {
+				return err
+			}
+
+			last = st
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return status.Error(codes.Canceled, "stream has ended")
+		case <-ticker.C:
+		}
+	}
+}
+
+// sharedReadiness return the readiness report shared by Watch streams,
+// the checks run at most once per HealthWatchInterval.
+func (h *healthServer) sharedReadiness() health.Report {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.checkedAt.IsZero() && time.Since(h.checkedAt) < HealthWatchInterval {
+		return h.report
+	}
+
+	// the checks have their own timeout,a canceled stream should not fail the shared report
+	h.report = h.s.engine.Health().Readiness(context.Background())
+	h.checkedAt = time.Now()
+	return h.report
+}
+
+// status return serving status of service,false if the service is unknown
+func (h *healthServer) status(service string,
+	readiness func() health.Report) (grpc_health_v1.HealthCheckResponse_ServingStatus, bool) {
+	if atomic.LoadInt32(&h.s.serving) == 0 {
+		return grpc_health_v1.HealthCheckResponse_NOT_SERVING, true
+	}
+
+	if h.s.engine == nil {
+		if service == "" {
+			return grpc_health_v1.HealthCheckResponse_SERVING, true
+		}
+
+		return grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN, false
+	}
+
+	report := readiness()
+	if service == "" {
+		return servingStatus(report.Status), true
+	}
+
+	for _, res := range report.Checks {
+		if res.Name == service {
+			return servingStatus(res.Status), true
+		}
+	}
+
+	return grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN, false
+}
+
+// servingStatus convert health status to grpc serving status
+func servingStatus(st health.Status) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	if st == health.StatusDown {
+		return grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	}
+
+	return grpc_health_v1.HealthCheckResponse_SERVING
+}
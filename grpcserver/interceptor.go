@@ -0,0 +1,98 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/go-god/msa/logger"
+)
+
+// RequestIDKey metadata key of request id
+var RequestIDKey = logger.XRequestID.String()
+
+// UnaryServerInterceptor set logger fields to the rpc context,
+// the request id is read from incoming metadata or generated,
+// and it is sent back by the response header.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(withLogContext(ctx, info.FullMethod), req)
+	}
+}
+
+// StreamServerInterceptor set logger fields to the stream context
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		return handler(srv, &serverStream{ServerStream: ss, ctx: withLogContext(ss.Context(), info.FullMethod)})
+	}
+}
+
+// UnaryClientInterceptor propagate the request id of context to outgoing metadata
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withOutgoingRequestID(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor propagate the request id of context to outgoing metadata
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withOutgoingRequestID(ctx), desc, cc, method, opts...)
+	}
+}
+
+// serverStream wrap grpc.ServerStream with a new context
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context return the wrapped context
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// withLogContext set request id, client ip, method and uri to context
+func withLogContext(ctx context.Context, fullMethod string) context.Context {
+	var reqID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(RequestIDKey); len(ids) > 0 {
+			reqID = ids[0]
+		}
+	}
+
+	if reqID == "" {
+		reqID = logger.NewRequestID()
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDKey, reqID))
+	ctx = context.WithValue(ctx, logger.XRequestID, reqID)
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ip := p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+
+		ctx = context.WithValue(ctx, logger.ReqClientIP, ip)
+	}
+
+	ctx = context.WithValue(ctx, logger.RequestMethod, "grpc")
+	return context.WithValue(ctx, logger.RequestURI, fullMethod)
+}
+
+// withOutgoingRequestID append request id of context to outgoing metadata
+func withOutgoingRequestID(ctx context.Context) context.Context {
+	reqID, ok := ctx.Value(logger.XRequestID).(string)
+	if !ok || reqID == "" {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, RequestIDKey, reqID)
+}
@@ -0,0 +1,246 @@
+// Package grpcserver grpc server component managed by msa engine
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/go-god/msa"
+	"github.com/go-god/msa/config"
+	"github.com/go-god/msa/provides"
+)
+
+// FactoryType component type of grpc server in components config section
+const FactoryType = "grpc_server"
+
+// ErrNotServing the server is not serving
+var ErrNotServing = errors.New("grpc server is not serving")
+
+func init() {
+	provides.RegisterFactory(FactoryType, func(c config.ConfigInterface, key string) (interface{}, error) {
+		return NewFromConfig(c, key)
+	})
+}
+
+// Service grpc service,it registers itself to grpc server.
+// The inject objects of Config.ServiceGroup which implement it will be registered on Start.
+type Service interface {
+	RegisterGRPC(s grpc.ServiceRegistrar)
+}
+
+// Server grpc server component,it implements Start, Drain, Stop and health Check,
+// and serves the standard grpc health service wired to the engine health state.
+type Server struct {
+	conf     Config
+	addr     string
+	opts     []grpc.ServerOption
+	services []Service
+	engine   *msa.Engine
+	server   *grpc.Server
+
+	serving      int32
+	shutdownOnce sync.Once
+	shutdownErr  error
+	mu           sync.Mutex // guard addr and serveErr
+	serveErr     error
+}
+
+// New create grpc server,opts are appended to the options built from config
+func New(conf Config, opts ...grpc.ServerOption) *Server {
+	return &Server{conf: conf, opts: opts}
+}
+
+// NewFromConfig create grpc server from config section key,
+// the fields which are not set in config use DefaultConfig.
+func NewFromConfig(c config.ConfigInterface, key string, opts ...grpc.ServerOption) (*Server, error) {
+	conf := DefaultConfig()
+	if err := c.GetValue(key, &conf); err != nil {
+		return nil, err
+	}
+
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+
+	return New(conf, opts...), nil
+}
+
+// Register add services,it must be called before Start
+func (s *Server) Register(services ...Service) {
+	s.services = append(s.services, services...)
+}
+
+// SetEngine set msa engine,it is called by the engine before Init
+func (s *Server) SetEngine(e *msa.Engine) {
+	s.engine = e
+}
+
+// Config return server config
+func (s *Server) Config() Config {
+	return s.conf
+}
+
+// Addr return the listening address after Start
+func (s *Server) Addr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addr
+}
+
+// Validate check server config,it is called by msa engine dry-run
+func (s *Server) Validate() error {
+	return s.conf.Validate()
+}
+
+// Start register services and serve in background
+func (s *Server) Start() error {
+	opts, err := s.serverOptions()
+	if err != nil {
+		return err
+	}
+
+	services := s.services
+	if s.engine != nil && s.conf.ServiceGroup != "" {
+		var groupServices []Service
+		if err = s.engine.ResolveGroup(s.conf.ServiceGroup, &groupServices); err != nil {
+			return err
+		}
+
+		services = append(services, groupServices...)
+	}
+
+	s.server = grpc.NewServer(opts...)
+	for _, svc := range services {
+		svc.RegisterGRPC(s.server)
+	}
+
+	grpc_health_v1.RegisterHealthServer(s.server, &healthServer{s: s})
+
+	ln, err := net.Listen("tcp", s.conf.Addr)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.addr = ln.Addr().String()
+	s.mu.Unlock()
+	atomic.StoreInt32(&s.serving, 1)
+	slog.Info("grpc server listening", "addr", ln.Addr().String())
+	go func() {
+		err := s.server.Serve(ln)
+		atomic.StoreInt32(&s.serving, 0)
+		if err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			slog.Error("grpc server serve error", "error", err)
+			s.mu.Lock()
+			s.serveErr = err
+			s.mu.Unlock()
+		}
+	}()
+
+	return nil
+}
+
+// Drain stop accepting new connections and wait in-flight rpcs by GracefulStop,
+// if ctx is done before that,the server will be stopped immediately.
+func (s *Server) Drain(ctx context.Context) error {
+	if s.server == nil {
+		return nil
+	}
+
+	return s.shutdown(ctx)
+}
+
+// Stop graceful stop server within ShutdownTimeout,it does nothing if the server has been drained
+func (s *Server) Stop() {
+	if s.server == nil {
+		return
+	}
+
+	ctx := context.Background()
+	if s.conf.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.conf.ShutdownTimeout)
+		defer cancel()
+	}
+
+	if err := s.shutdown(ctx); err != nil {
+		slog.Error("grpc server shutdown error", "error", err)
+	}
+}
+
+// shutdown graceful stop the server once,Drain and Stop share the result
+func (s *Server) shutdown(ctx context.Context) error {
+	s.shutdownOnce.Do(func() {
+		atomic.StoreInt32(&s.serving, 0)
+		done := make(chan struct{})
+		go func() {
+			s.server.GracefulStop()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			s.server.Stop()
+			s.shutdownErr = ctx.Err()
+		}
+	})
+
+	return s.shutdownErr
+}
+
+// Check health check,it fails if the server is not serving
+func (s *Server) Check(ctx context.Context) error {
+	s.mu.Lock()
+	err := s.serveErr
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if atomic.LoadInt32(&s.serving) == 0 {
+		return ErrNotServing
+	}
+
+	return nil
+}
+
+// serverOptions build grpc server options from config
+func (s *Server) serverOptions() ([]grpc.ServerOption, error) {
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(StreamServerInterceptor()),
+	}
+
+	if s.conf.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(s.conf.MaxRecvMsgSize))
+	}
+
+	if s.conf.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(s.conf.MaxSendMsgSize))
+	}
+
+	if s.conf.ConnectionTimeout > 0 {
+		opts = append(opts, grpc.ConnectionTimeout(s.conf.ConnectionTimeout))
+	}
+
+	if s.conf.CertFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(s.conf.CertFile, s.conf.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	return append(opts, s.opts...), nil
+}
@@ -0,0 +1,132 @@
+package grpcserver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/go-god/msa"
+	"github.com/go-god/msa/config"
+	"github.com/go-god/msa/health"
+	"github.com/go-god/msa/logger"
+)
+
+// TestServer test server lifecycle, health service and request id propagation.
+func TestServer(t *testing.T) {
+	conf := DefaultConfig()
+	conf.Addr = "127.0.0.1:0"
+	s := New(conf)
+	addr := make(chan string)
+	go func() { addr <- s.Addr() }()
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	<-addr
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, s.Addr(), grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var header metadata.MD
+	ctx = context.WithValue(ctx, logger.XRequestID, "abc")
+	resp, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{},
+		grpc.Header(&header))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Fatalf("health status: %v", resp.Status)
+	}
+
+	if ids := header.Get(RequestIDKey); len(ids) != 1 || ids[0] != "abc" {
+		t.Fatalf("request id header: %v", ids)
+	}
+
+	if err = s.Check(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = s.Drain(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// the server is shut down once,a later Drain returns the first result
+	canceled, cancelNow := context.WithCancel(ctx)
+	cancelNow()
+	if err = s.Drain(canceled); err != nil {
+		t.Fatalf("drain again: %v", err)
+	}
+
+	s.Stop()
+	if err = s.Check(ctx); err != ErrNotServing {
+		t.Fatalf("check after stop: %v", err)
+	}
+}
+
+// TestHealthWatchShared test Watch streams share the readiness report.
+func TestHealthWatchShared(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.yaml"), []byte("app: test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var checks int32
+	e := msa.New(msa.WithConfigInterface(config.New(config.WithConfigDir(dir), config.WithConfigFile("app.yaml"))),
+		msa.WithHealthChecker("db", health.CheckerFunc(func(ctx context.Context) error {
+			atomic.AddInt32(&checks, 1)
+			return nil
+		})))
+	e.Health().SetReady(true)
+
+	interval := HealthWatchInterval
+	HealthWatchInterval = time.Hour
+	defer func() { HealthWatchInterval = interval }()
+
+	conf := DefaultConfig()
+	conf.Addr = "127.0.0.1:0"
+	s := New(conf)
+	s.SetEngine(e)
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, s.Addr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	client := grpc_health_v1.NewHealthClient(conn)
+	for _, service := range []string{"", "db", ""} {
+		stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := stream.Recv()
+		if err != nil || resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+			t.Fatalf("watch %q status: %v error: %v", service, resp.GetStatus(), err)
+		}
+	}
+
+	if n := atomic.LoadInt32(&checks); n != 1 {
+		t.Fatalf("readiness checks: %d", n)
+	}
+}
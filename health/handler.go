@@ -0,0 +1,52 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	// LivenessPath liveness http path
+	LivenessPath = "/healthz"
+	// ReadinessPath readiness http path
+	ReadinessPath = "/readyz"
+)
+
+// Handler return http handler which serves LivenessPath and ReadinessPath
+func (r *Registry) Handler() http.Handler {
+	mux := http.NewServeMux()
+	r.Mount(mux)
+	return mux
+}
+
+// Mount register LivenessPath and ReadinessPath to mux
+func (r *Registry) Mount(mux *http.ServeMux) {
+	mux.Handle(LivenessPath, r.LivenessHandler())
+	mux.Handle(ReadinessPath, r.ReadinessHandler())
+}
+
+// LivenessHandler liveness http handler
+func (r *Registry) LivenessHandler() http.Handler {
+	return reportHandler(r.Liveness)
+}
+
+// ReadinessHandler readiness http handler
+func (r *Registry) ReadinessHandler() http.Handler {
+	return reportHandler(r.Readiness)
+}
+
+// reportHandler write report as json,the status code is 503 if the status is down
+func reportHandler(fn func(ctx context.Context) Report) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		report := fn(req.Context())
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if report.Status == StatusDown {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
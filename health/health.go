@@ -0,0 +1,256 @@
+// Package health for liveness and readiness checks
+package health
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTimeout default timeout of a check
+var DefaultTimeout = 3 * time.Second
+
+// ErrNotReady the registry is not ready
+var ErrNotReady = errors.New("not ready")
+
+// Checker health check interface,components can implement it
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc func as Checker
+type CheckerFunc func(ctx context.Context) error
+
+// Check implements Checker interface
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Describer optional interface for a Checker to set its check options
+type Describer interface {
+	HealthOptions() []Option
+}
+
+// Level check criticality level
+type Level int
+
+const (
+	// Critical if the check fails,the status will be down
+	Critical Level = iota
+	// NonCritical if the check fails,the status will be degraded
+	NonCritical
+)
+
+// Kind check kind,it can be combined by |
+type Kind int
+
+const (
+	// Liveness the check is used by liveness
+	Liveness Kind = 1 << iota
+	// Readiness the check is used by readiness
+	Readiness
+)
+
+// Status health status
+type Status string
+
+const (
+	// StatusUp all checks pass
+	StatusUp Status = "up"
+	// StatusDegraded some non-critical checks fail
+	StatusDegraded Status = "degraded"
+	// StatusDown some critical checks fail
+	StatusDown Status = "down"
+)
+
+// Result check result
+type Result struct {
+	Name      string        `json:"name"`
+	Status    Status        `json:"status"`
+	Critical  bool          `json:"critical"`
+	Error     string        `json:"error,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	CheckedAt time.Time     `json:"checked_at"`
+	Cached    bool          `json:"cached,omitempty"`
+}
+
+// Report aggregated check results
+type Report struct {
+	Status Status   `json:"status"`
+	Checks []Result `json:"checks"`
+}
+
+// check registered checker
+type check struct {
+	name     string
+	checker  Checker
+	timeout  time.Duration
+	cacheTTL time.Duration
+	level    Level
+	kind     Kind
+
+	mu   sync.Mutex
+	last *Result
+}
+
+// Registry health checks registry
+type Registry struct {
+	mu     sync.RWMutex
+	checks []*check
+	ready  int32
+}
+
+// New create a health registry,it is not ready until SetReady(true)
+func New() *Registry {
+	return &Registry{}
+}
+
+// Register register checker by name,the default options are:
+// Critical, Readiness, DefaultTimeout and no cache.
+// If checker implements Describer,its options are applied before opts.
+func (r *Registry) Register(name string, checker Checker, opts ...Option) {
+	c := &check{
+		name:    name,
+		checker: checker,
+		timeout: DefaultTimeout,
+		level:   Critical,
+		kind:    Readiness,
+	}
+
+	if d, ok := checker.(Describer); ok {
+		opts = append(d.HealthOptions(), opts...)
+	}
+
+	for _, o := range opts {
+		o(c)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, c)
+}
+
+// SetReady set readiness state,if it is false the readiness will be down
+func (r *Registry) SetReady(b bool) {
+	var v int32
+	if b {
+		v = 1
+	}
+
+	atomic.StoreInt32(&r.ready, v)
+}
+
+// IsReady return readiness state
+func (r *Registry) IsReady() bool {
+	return atomic.LoadInt32(&r.ready) == 1
+}
+
+// Liveness run all liveness checks
+func (r *Registry) Liveness(ctx context.Context) Report {
+	return r.run(ctx, Liveness)
+}
+
+// Readiness run all readiness checks,the status is down if the registry is not ready
+func (r *Registry) Readiness(ctx context.Context) Report {
+	report := r.run(ctx, Readiness)
+	if !r.IsReady() {
+		report.Status = StatusDown
+		report.Checks = append(report.Checks, Result{
+			Name:      "engine",
+			Status:    StatusDown,
+			Critical:  true,
+			Error:     ErrNotReady.Error(),
+			CheckedAt: time.Now(),
+		})
+	}
+
+	return report
+}
+
+// run all checks of kind concurrently
+func (r *Registry) run(ctx context.Context, kind Kind) Report {
+	r.mu.RLock()
+	checks := make([]*check, 0, len(r.checks))
+	for _, c := range r.checks {
+		if c.kind&kind != 0 {
+			checks = append(checks, c)
+		}
+	}
+	r.mu.RUnlock()
+
+	report := Report{Status: StatusUp, Checks: make([]Result, len(checks))}
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c *check) {
+			defer wg.Done()
+			report.Checks[i] = c.run(ctx)
+		}(i, c)
+	}
+
+	wg.Wait()
+
+	sort.Slice(report.Checks, func(i, j int) bool {
+		return report.Checks[i].Name < report.Checks[j].Name
+	})
+
+	for _, res := range report.Checks {
+		if res.Status == StatusUp {
+			continue
+		}
+
+		if res.Critical {
+			report.Status = StatusDown
+		} else if report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+
+	return report
+}
+
+// run the check,if the last result is not expired it will be returned
+func (c *check) run(ctx context.Context) Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.last != nil && c.cacheTTL > 0 && time.Since(c.last.CheckedAt) < c.cacheTTL {
+		res := *c.last
+		res.Cached = true
+		return res
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- c.checker.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	res := Result{
+		Name:      c.name,
+		Status:    StatusUp,
+		Critical:  c.level == Critical,
+		Duration:  time.Since(start),
+		CheckedAt: start,
+	}
+
+	if err != nil {
+		res.Status = StatusDown
+		res.Error = err.Error()
+	}
+
+	c.last = &res
+	return res
+}
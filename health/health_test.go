@@ -0,0 +1,58 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestRegistry test liveness and readiness aggregation.
+func TestRegistry(t *testing.T) {
+	r := New()
+	calls := 0
+	r.Register("db", CheckerFunc(func(ctx context.Context) error {
+		calls++
+		return nil
+	}), WithKind(Liveness|Readiness), WithCacheTTL(time.Minute))
+	r.Register("cache", CheckerFunc(func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}), WithLevel(NonCritical))
+	r.Register("slow", CheckerFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}), WithKind(Liveness), WithTimeout(10*time.Millisecond))
+
+	ctx := context.Background()
+	if report := r.Liveness(ctx); report.Status != StatusDown || len(report.Checks) != 2 {
+		t.Fatalf("liveness report: %+v", report)
+	}
+
+	if report := r.Readiness(ctx); report.Status != StatusDown {
+		t.Fatalf("readiness report before ready: %+v", report)
+	}
+
+	r.SetReady(true)
+	report := r.Readiness(ctx)
+	if report.Status != StatusDegraded || report.Checks[0].Name != "cache" || !report.Checks[1].Cached {
+		t.Fatalf("readiness report: %+v", report)
+	}
+
+	if calls != 1 {
+		t.Fatalf("db check calls: %d", calls)
+	}
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, LivenessPath, nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("liveness status code: %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ReadinessPath, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("readiness status code: %d", rec.Code)
+	}
+}
@@ -0,0 +1,36 @@
+package health
+
+import (
+	"time"
+)
+
+// Option check option
+type Option func(c *check)
+
+// WithTimeout set check timeout
+func WithTimeout(t time.Duration) Option {
+	return func(c *check) {
+		c.timeout = t
+	}
+}
+
+// WithCacheTTL the check result will be cached for ttl
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(c *check) {
+		c.cacheTTL = ttl
+	}
+}
+
+// WithLevel set check criticality level
+func WithLevel(level Level) Option {
+	return func(c *check) {
+		c.level = level
+	}
+}
+
+// WithKind set check kind,eg: health.Liveness|health.Readiness
+func WithKind(kind Kind) Option {
+	return func(c *check) {
+		c.kind = kind
+	}
+}
@@ -0,0 +1,61 @@
+package httpserver
+
+import (
+	"errors"
+	"time"
+)
+
+// Config http server config
+/**
+http_server:
+  addr: ":8080"
+  read_timeout: 5s
+  read_header_timeout: 2s
+  write_timeout: 10s
+  idle_timeout: 60s
+  shutdown_timeout: 5s
+  max_header_bytes: 1048576
+  cert_file: ""
+  key_file: ""
+  access_log: false
+  trust_proxy: false
+*/
+type Config struct {
+	Addr              string        `json:"addr" mapstructure:"addr"`
+	ReadTimeout       time.Duration `json:"read_timeout" mapstructure:"read_timeout"`
+	ReadHeaderTimeout time.Duration `json:"read_header_timeout" mapstructure:"read_header_timeout"`
+	WriteTimeout      time.Duration `json:"write_timeout" mapstructure:"write_timeout"`
+	IdleTimeout       time.Duration `json:"idle_timeout" mapstructure:"idle_timeout"`
+	ShutdownTimeout   time.Duration `json:"shutdown_timeout" mapstructure:"shutdown_timeout"`
+	MaxHeaderBytes    int           `json:"max_header_bytes" mapstructure:"max_header_bytes"`
+	CertFile          string        `json:"cert_file" mapstructure:"cert_file"`
+	KeyFile           string        `json:"key_file" mapstructure:"key_file"`
+	AccessLog         bool          `json:"access_log" mapstructure:"access_log"`
+	TrustProxy        bool          `json:"trust_proxy" mapstructure:"trust_proxy"`
+}
+
+// DefaultConfig return default http server config
+func DefaultConfig() Config {
+	return Config{
+		Addr:              ":8080",
+		ReadTimeout:       5 * time.Second,
+		ReadHeaderTimeout: 2 * time.Second,
+		WriteTimeout:      10 * time.Second,
+		IdleTimeout:       60 * time.Second,
+		ShutdownTimeout:   5 * time.Second,
+		MaxHeaderBytes:    1 << 20,
+	}
+}
+
+// Validate check config
+func (c *Config) Validate() error {
+	if c.Addr == "" {
+		return errors.New("http server addr is empty")
+	}
+
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return errors.New("http server cert_file and key_file must be set together")
+	}
+
+	return nil
+}
@@ -0,0 +1,205 @@
+// Package httpserver http server component managed by msa engine
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-god/msa/config"
+	"github.com/go-god/msa/middleware"
+	"github.com/go-god/msa/provides"
+	"github.com/go-god/msa/tracing"
+)
+
+// FactoryType component type of http server in components config section
+const FactoryType = "http_server"
+
+// ErrNotServing the server is not serving
+var ErrNotServing = errors.New("http server is not serving")
+
+func init() {
+	provides.RegisterFactory(FactoryType, func(c config.ConfigInterface, key string) (interface{}, error) {
+		return NewFromConfig(c, key, nil)
+	})
+}
+
+// Server http server component,it implements Start, Drain, Stop and health Check.
+// Each request starts a server span by tracing.Middleware,
+// and its context carries the logger fields set by middleware.LogContext.
+type Server struct {
+	conf    Config
+	addr    string
+	server  *http.Server
+	mux     *http.ServeMux
+	handler http.Handler
+
+	serving      int32
+	shutdownOnce sync.Once
+	shutdownErr  error
+	mu           sync.Mutex
+	serveErr     error
+}
+
+// New create http server,if handler is nil an internal ServeMux will be used,
+// and handlers can be registered by Handle or HandleFunc before Start.
+func New(conf Config, handler http.Handler) *Server {
+	s := &Server{
+		conf:    conf,
+		mux:     http.NewServeMux(),
+		handler: handler,
+	}
+
+	return s
+}
+
+// NewFromConfig create http server from config section key,
+// the fields which are not set in config use DefaultConfig.
+func NewFromConfig(c config.ConfigInterface, key string, handler http.Handler) (*Server, error) {
+	conf := DefaultConfig()
+	if err := c.GetValue(key, &conf); err != nil {
+		return nil, err
+	}
+
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+
+	return New(conf, handler), nil
+}
+
+// Handle register handler for pattern on the internal ServeMux
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// HandleFunc register handler func for pattern on the internal ServeMux
+func (s *Server) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	s.mux.HandleFunc(pattern, handler)
+}
+
+// SetHandler replace the handler,it must be called before Start
+func (s *Server) SetHandler(handler http.Handler) {
+	s.handler = handler
+}
+
+// Config return server config
+func (s *Server) Config() Config {
+	return s.conf
+}
+
+// Validate check server config,it is called by msa engine dry-run
+func (s *Server) Validate() error {
+	return s.conf.Validate()
+}
+
+// Addr return the listening address after Start
+func (s *Server) Addr() string {
+	return s.addr
+}
+
+// Start listen addr and serve in background
+func (s *Server) Start() error {
+	handler := s.handler
+	if handler == nil {
+		handler = s.mux
+	}
+
+	handler = middleware.LogContext(middleware.WithAccessLog(s.conf.AccessLog),
+		middleware.WithTrustProxy(s.conf.TrustProxy))(handler)
+	s.server = &http.Server{
+		Addr:              s.conf.Addr,
+		Handler:           tracing.Middleware(handler),
+		ReadTimeout:       s.conf.ReadTimeout,
+		ReadHeaderTimeout: s.conf.ReadHeaderTimeout,
+		WriteTimeout:      s.conf.WriteTimeout,
+		IdleTimeout:       s.conf.IdleTimeout,
+		MaxHeaderBytes:    s.conf.MaxHeaderBytes,
+	}
+
+	ln, err := net.Listen("tcp", s.conf.Addr)
+	if err != nil {
+		return err
+	}
+
+	s.addr = ln.Addr().String()
+	atomic.StoreInt32(&s.serving, 1)
+	slog.Info("http server listening", "addr", ln.Addr().String())
+	go func() {
+		var err error
+		if s.conf.CertFile != "" {
+			err = s.server.ServeTLS(ln, s.conf.CertFile, s.conf.KeyFile)
+		} else {
+			err = s.server.Serve(ln)
+		}
+
+		atomic.StoreInt32(&s.serving, 0)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("http server serve error", "error", err)
+			s.mu.Lock()
+			s.serveErr = err
+			s.mu.Unlock()
+		}
+	}()
+
+	return nil
+}
+
+// Drain stop accepting new connections and wait in-flight requests until ctx is done
+func (s *Server) Drain(ctx context.Context) error {
+	if s.server == nil {
+		return nil
+	}
+
+	return s.shutdown(ctx)
+}
+
+// Stop shutdown server within ShutdownTimeout,the remaining connections will be closed.
+// If the server has been drained,it only closes the connections left by a failed Drain.
+func (s *Server) Stop() {
+	if s.server == nil {
+		return
+	}
+
+	ctx := context.Background()
+	if s.conf.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.conf.ShutdownTimeout)
+		defer cancel()
+	}
+
+	if err := s.shutdown(ctx); err != nil {
+		slog.Error("http server shutdown error", "error", err)
+		_ = s.server.Close()
+	}
+}
+
+// shutdown gracefully shutdown the server once,Drain and Stop share the result
+func (s *Server) shutdown(ctx context.Context) error {
+	s.shutdownOnce.Do(func() {
+		atomic.StoreInt32(&s.serving, 0)
+		s.shutdownErr = s.server.Shutdown(ctx)
+	})
+
+	return s.shutdownErr
+}
+
+// Check health check,it fails if the server is not serving
+func (s *Server) Check(ctx context.Context) error {
+	s.mu.Lock()
+	err := s.serveErr
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if atomic.LoadInt32(&s.serving) == 0 {
+		return ErrNotServing
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package httpserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-god/msa/logger"
+)
+
+// TestServer test server lifecycle and request logger fields.
+func TestServer(t *testing.T) {
+	conf := DefaultConfig()
+	conf.Addr = "127.0.0.1:0"
+	s := New(conf, nil)
+	s.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		fmt.Fprintf(w, "%v %v %v", ctx.Value(logger.XRequestID), ctx.Value(logger.RequestMethod),
+			ctx.Value(logger.RequestURI))
+	})
+
+	if err := s.Check(context.Background()); err != ErrNotServing {
+		t.Fatalf("check before start: %v", err)
+	}
+
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://"+s.Addr()+"/hello?a=1", nil)
+	req.Header.Set("X-Request-ID", "abc")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(b) != "abc GET /hello?a=1" {
+		t.Fatalf("response body: %s", b)
+	}
+
+	if err = s.Check(context.Background()); err != nil {
+		t.Fatalf("check after start: %v", err)
+	}
+
+	shutdowns := make(chan struct{}, 2)
+	s.server.RegisterOnShutdown(func() { shutdowns <- struct{}{} })
+	if err = s.Drain(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Stop()
+	<-shutdowns
+	select {
+	case <-shutdowns:
+		t.Fatal("server shutdown twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err = s.Check(context.Background()); err != ErrNotServing {
+		t.Fatalf("check after stop: %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+// Package idgen RFC 9562 (RFC 4122) uuid, ulid and snowflake id generators
+package idgen
+
+import (
+	"crypto/rand"
+	"io"
+)
+
+// IDGenerator id generator interface
+type IDGenerator interface {
+	// NewID return a new unique id
+	NewID() string
+}
+
+// GeneratorFunc func as IDGenerator
+type GeneratorFunc func() string
+
+// NewID implements IDGenerator
+func (f GeneratorFunc) NewID() string {
+	return f()
+}
+
+var (
+	// UUIDv4 random uuid generator,eg: 0f8fad5b-d9cb-469f-a165-70867728950e
+	UUIDv4 IDGenerator = GeneratorFunc(NewUUIDv4)
+
+	// UUIDv7 time-ordered uuid generator,eg: 01890a5d-ac96-774b-bcce-b302099a8057
+	UUIDv7 IDGenerator = GeneratorFunc(NewUUIDv7)
+
+	// ULID time-ordered ulid generator,eg: 01ARZ3NDEKTSV4RRFFQ69G5FAV
+	ULID IDGenerator = GeneratorFunc(NewULID)
+)
+
+// randRead fill b with crypto random bytes
+func randRead(b []byte) {
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		panic("idgen: read random bytes error: " + err.Error())
+	}
+}
@@ -0,0 +1,80 @@
+package idgen
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var (
+	uuidRegexp = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-([47])[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	ulidRegexp = regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+)
+
+// TestUUID test uuid version,variant and uniqueness.
+func TestUUID(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		for version, id := range map[string]string{"4": UUIDv4.NewID(), "7": UUIDv7.NewID()} {
+			m := uuidRegexp.FindStringSubmatch(id)
+			if m == nil || m[1] != version || seen[id] {
+				t.Fatalf("invalid uuid v%s: %s", version, id)
+			}
+
+			seen[id] = true
+		}
+	}
+}
+
+// TestTimeOrdered test uuid v7 and ulid are ordered in the same millisecond.
+func TestTimeOrdered(t *testing.T) {
+	for name, g := range map[string]IDGenerator{"uuidv7": UUIDv7, "ulid": ULID} {
+		ids := make([]string, 5000)
+		for i := range ids {
+			ids[i] = g.NewID()
+		}
+
+		if !sort.StringsAreSorted(ids) {
+			t.Fatalf("%s ids are not sorted", name)
+		}
+
+		if name == "ulid" && !ulidRegexp.MatchString(ids[0]) {
+			t.Fatalf("invalid ulid: %s", ids[0])
+		}
+	}
+}
+
+// TestSnowflake test snowflake node id and ordering.
+func TestSnowflake(t *testing.T) {
+	if _, err := NewSnowflake(1024, time.Time{}); err != ErrInvalidNode {
+		t.Fatalf("node 1024 error: %v", err)
+	}
+
+	s, err := NewSnowflake(5, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var last int64
+	for i := 0; i < 10000; i++ {
+		id := s.NextID()
+		if id <= last || (id>>snowflakeSeqBits)&snowflakeMaxNode != 5 {
+			t.Fatalf("invalid snowflake id: %d after %d", id, last)
+		}
+
+		last = id
+	}
+
+	if _, err = strconv.ParseInt(s.NewID(), 10, 64); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// BenchmarkUUIDv7 benchmark uuid v7
+func BenchmarkUUIDv7(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = NewUUIDv7()
+	}
+}
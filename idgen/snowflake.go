@@ -0,0 +1,90 @@
+package idgen
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	snowflakeNodeBits = 10
+	snowflakeSeqBits  = 12
+	snowflakeMaxNode  = 1<<snowflakeNodeBits - 1
+	snowflakeMaxSeq   = 1<<snowflakeSeqBits - 1
+)
+
+// DefaultSnowflakeEpoch default snowflake epoch 2020-01-01 00:00:00 UTC
+var DefaultSnowflakeEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ErrInvalidNode snowflake node id is out of range
+var ErrInvalidNode = errors.New("snowflake node id must be in [0,1023]")
+
+// Snowflake snowflake-style id generator,the 63-bit id is composed of
+// 41-bit milliseconds since epoch, 10-bit node id and 12-bit sequence.
+type Snowflake struct {
+	mu     sync.Mutex
+	epoch  int64 // epoch in milliseconds
+	node   int64
+	lastMS int64
+	seq    int64
+}
+
+// NewSnowflake create snowflake generator with node id in [0,1023],
+// if epoch is zero DefaultSnowflakeEpoch will be used.
+func NewSnowflake(node int64, epoch time.Time) (*Snowflake, error) {
+	if node < 0 || node > snowflakeMaxNode {
+		return nil, ErrInvalidNode
+	}
+
+	if epoch.IsZero() {
+		epoch = DefaultSnowflakeEpoch
+	}
+
+	return &Snowflake{epoch: epoch.UnixNano() / int64(time.Millisecond), node: node}, nil
+}
+
+// NextID return next snowflake id,it waits for the next millisecond
+// when the sequence is exhausted or the clock moves backwards.
+func (s *Snowflake) NextID() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ms := s.now()
+	if ms < s.lastMS {
+		ms = s.waitAfter(s.lastMS - 1)
+	}
+
+	if ms == s.lastMS {
+		s.seq = (s.seq + 1) & snowflakeMaxSeq
+		if s.seq == 0 {
+			ms = s.waitAfter(s.lastMS)
+		}
+	} else {
+		s.seq = 0
+	}
+
+	s.lastMS = ms
+	return (ms-s.epoch)<<(snowflakeNodeBits+snowflakeSeqBits) | s.node<<snowflakeSeqBits | s.seq
+}
+
+// NewID implements IDGenerator,return decimal snowflake id
+func (s *Snowflake) NewID() string {
+	return strconv.FormatInt(s.NextID(), 10)
+}
+
+// now return current unix milliseconds
+func (s *Snowflake) now() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
+
+// waitAfter wait until the clock is after ms
+func (s *Snowflake) waitAfter(ms int64) int64 {
+	now := s.now()
+	for now <= ms {
+		time.Sleep(time.Duration(ms-now+1) * time.Millisecond / 2)
+		now = s.now()
+	}
+
+	return now
+}
@@ -0,0 +1,94 @@
+package idgen
+
+import (
+	"sync"
+	"time"
+)
+
+// crockford base32 alphabet
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidState state for monotonic ulid
+var ulidState struct {
+	sync.Mutex
+	lastMS  int64
+	entropy [10]byte
+}
+
+// NewULID return a time-ordered ulid,the ulids generated in the same
+// millisecond are ordered by incrementing the 80-bit randomness.
+func NewULID() string {
+	var id [16]byte
+
+	ulidState.Lock()
+	ms := time.Now().UnixNano() / int64(time.Millisecond)
+	if ms <= ulidState.lastMS {
+		ms = ulidState.lastMS
+		if !incEntropy(&ulidState.entropy) {
+			// randomness overflow,borrow the next millisecond
+			ms++
+			randRead(ulidState.entropy[:])
+		}
+	} else {
+		randRead(ulidState.entropy[:])
+	}
+
+	ulidState.lastMS = ms
+	copy(id[6:], ulidState.entropy[:])
+	ulidState.Unlock()
+
+	id[0] = byte(ms >> 40)
+	id[1] = byte(ms >> 32)
+	id[2] = byte(ms >> 24)
+	id[3] = byte(ms >> 16)
+	id[4] = byte(ms >> 8)
+	id[5] = byte(ms)
+	return encodeULID(id)
+}
+
+// incEntropy increment entropy by 1,false if it overflows
+func incEntropy(b *[10]byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// encodeULID encode 128-bit id to 26 crockford base32 chars
+func encodeULID(id [16]byte) string {
+	var dst [26]byte
+	// 10 bytes timestamp chars
+	dst[0] = crockford[(id[0]&224)>>5]
+	dst[1] = crockford[id[0]&31]
+	dst[2] = crockford[(id[1]&248)>>3]
+	dst[3] = crockford[((id[1]&7)<<2)|((id[2]&192)>>6)]
+	dst[4] = crockford[(id[2]&62)>>1]
+	dst[5] = crockford[((id[2]&1)<<4)|((id[3]&240)>>4)]
+	dst[6] = crockford[((id[3]&15)<<1)|((id[4]&128)>>7)]
+	dst[7] = crockford[(id[4]&124)>>2]
+	dst[8] = crockford[((id[4]&3)<<3)|((id[5]&224)>>5)]
+	dst[9] = crockford[id[5]&31]
+
+	// 16 bytes entropy chars
+	dst[10] = crockford[(id[6]&248)>>3]
+	dst[11] = crockford[((id[6]&7)<<2)|((id[7]&192)>>6)]
+	dst[12] = crockford[(id[7]&62)>>1]
+	dst[13] = crockford[((id[7]&1)<<4)|((id[8]&240)>>4)]
+	dst[14] = crockford[((id[8]&15)<<1)|((id[9]&128)>>7)]
+	dst[15] = crockford[(id[9]&124)>>2]
+	dst[16] = crockford[((id[9]&3)<<3)|((id[10]&224)>>5)]
+	dst[17] = crockford[id[10]&31]
+	dst[18] = crockford[(id[11]&248)>>3]
+	dst[19] = crockford[((id[11]&7)<<2)|((id[12]&192)>>6)]
+	dst[20] = crockford[(id[12]&62)>>1]
+	dst[21] = crockford[((id[12]&1)<<4)|((id[13]&240)>>4)]
+	dst[22] = crockford[((id[13]&15)<<1)|((id[14]&128)>>7)]
+	dst[23] = crockford[(id[14]&124)>>2]
+	dst[24] = crockford[((id[14]&3)<<3)|((id[15]&224)>>5)]
+	dst[25] = crockford[id[15]&31]
+	return string(dst[:])
+}
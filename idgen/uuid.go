@@ -0,0 +1,83 @@
+package idgen
+
+import (
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// UUID 128-bit uuid
+type UUID [16]byte
+
+// String return uuid in 8-4-4-4-12 format
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// Version return uuid version
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// NewUUIDv4 return a random uuid version 4
+func NewUUIDv4() string {
+	var u UUID
+	randRead(u[:])
+	u[6] = (u[6] & 0x0f) | 0x40 // version 4
+	u[8] = (u[8] & 0x3f) | 0x80 // variant 10
+	return u.String()
+}
+
+// uuidV7 state for monotonic uuid version 7
+var uuidV7 struct {
+	sync.Mutex
+	lastMS  int64
+	counter uint16 // 12-bit counter in rand_a
+}
+
+// NewUUIDv7 return a time-ordered uuid version 7,
+// the uuids generated in the same millisecond are ordered by a 12-bit counter.
+func NewUUIDv7() string {
+	var u UUID
+	randRead(u[6:])
+
+	uuidV7.Lock()
+	ms := time.Now().UnixNano() / int64(time.Millisecond)
+	if ms <= uuidV7.lastMS {
+		ms = uuidV7.lastMS
+		uuidV7.counter++
+		if uuidV7.counter > 0xfff {
+			// counter overflow,borrow the next millisecond
+			ms++
+			uuidV7.counter = 0
+		}
+	} else {
+		// start the counter at a random value with the highest bit cleared
+		uuidV7.counter = (uint16(u[6])<<8 | uint16(u[7])) & 0x7ff
+	}
+
+	uuidV7.lastMS = ms
+	counter := uuidV7.counter
+	uuidV7.Unlock()
+
+	u[0] = byte(ms >> 40)
+	u[1] = byte(ms >> 32)
+	u[2] = byte(ms >> 24)
+	u[3] = byte(ms >> 16)
+	u[4] = byte(ms >> 8)
+	u[5] = byte(ms)
+	u[6] = 0x70 | byte(counter>>8) // version 7
+	u[7] = byte(counter)
+	u[8] = (u[8] & 0x3f) | 0x80 // variant 10
+	return u.String()
+}
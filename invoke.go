@@ -0,0 +1,114 @@
+package msa
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/go-god/msa/config"
+	"github.com/go-god/msa/logger"
+)
+
+var (
+	engineType  = reflect.TypeOf((*Engine)(nil))
+	configType  = reflect.TypeOf((*config.ConfigInterface)(nil)).Elem()
+	loggerType  = reflect.TypeOf((*logger.Logger)(nil)).Elem()
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// invokeFuncs call all invoke funcs in order,it will stop at the first error.
+func (e *Engine) invokeFuncs() error {
+	for _, fn := range e.invokeFunc {
+		if err := e.callInvokeFunc(fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkInvokeFuncs check all invoke funcs can be called without calling them
+func (e *Engine) checkInvokeFuncs() error {
+	for _, fn := range e.invokeFunc {
+		if err := e.checkInvokeFunc(fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkInvokeFunc check fn is an invoke func and all its parameters can be found
+func (e *Engine) checkInvokeFunc(fn interface{}) error {
+	fv := reflect.ValueOf(fn)
+	if fv.Kind() != reflect.Func || fv.IsNil() {
+		return fmt.Errorf("invoke func must be a func,got %T", fn)
+	}
+
+	ft := fv.Type()
+	if ft.NumOut() > 1 || (ft.NumOut() == 1 && ft.Out(0) != errorType) {
+		return fmt.Errorf("invoke func %s must return nothing or an error", ft)
+	}
+
+	for i := 0; i < ft.NumIn(); i++ {
+		if !e.hasInvokeArg(ft.In(i)) {
+			return fmt.Errorf("invoke func %s parameter %d type %s not found", ft, i, ft.In(i))
+		}
+	}
+
+	return nil
+}
+
+// callInvokeFunc call an invoke func
+// fn must be a func,its parameters can be:
+// *msa.Engine, config.ConfigInterface, logger.Logger, context.Context
+// or any type which is assignable from an inject object value.
+// fn can return nothing or an error.
+func (e *Engine) callInvokeFunc(fn interface{}) error {
+	if err := e.checkInvokeFunc(fn); err != nil {
+		return err
+	}
+
+	fv := reflect.ValueOf(fn)
+	args := make([]reflect.Value, fv.Type().NumIn())
+	for i := range args {
+		args[i], _ = e.invokeArg(fv.Type().In(i))
+	}
+
+	out := fv.Call(args)
+	if len(out) == 1 && !out[0].IsNil() {
+		return out[0].Interface().(error)
+	}
+
+	return nil
+}
+
+// hasInvokeArg check invoke func parameter value of the type can be found
+func (e *Engine) hasInvokeArg(t reflect.Type) bool {
+	switch t {
+	case engineType, configType, loggerType, contextType:
+		return true
+	}
+
+	_, ok := e.findObject("", t)
+	return ok
+}
+
+// invokeArg find invoke func parameter value by type
+func (e *Engine) invokeArg(t reflect.Type) (reflect.Value, bool) {
+	switch t {
+	case engineType:
+		return reflect.ValueOf(e), true
+	case configType:
+		return reflect.ValueOf(&e.configInterface).Elem(), true
+	case loggerType:
+		l := logger.DefaultLogger()
+		return reflect.ValueOf(&l).Elem(), true
+	case contextType:
+		ctx := context.Background()
+		return reflect.ValueOf(&ctx).Elem(), true
+	}
+
+	return e.findObject("", t)
+}
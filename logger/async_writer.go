@@ -0,0 +1,190 @@
+package logger
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// OverflowPolicy 异步写日志队列满时的处理策略
+type OverflowPolicy int
+
+const (
+	// OverflowBlock 阻塞等待队列有空位，不丢日志
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDrop 丢弃新日志
+	OverflowDrop
+
+	// OverflowDropOldest 丢弃队列中最早的日志
+	OverflowDropOldest
+)
+
+// dropReasonOverflow 异步写日志队列满被丢弃
+const dropReasonOverflow = "overflow"
+
+// defaultAsyncSyncTimeout Sync等待队列写入的默认超时时间
+const defaultAsyncSyncTimeout = 5 * time.Second
+
+// errAsyncSyncTimeout Sync等待队列写入超时，例如ws写入阻塞
+var errAsyncSyncTimeout = errors.New("async log writer sync timeout")
+
+// asyncOption 异步写日志配置
+type asyncOption struct {
+	queueSize int
+	policy    OverflowPolicy
+}
+
+// asyncEntry 队列中的一行日志，flushed不为空时表示Sync标记
+type asyncEntry struct {
+	p       []byte
+	flushed chan struct{}
+}
+
+// asyncWriter 异步写日志，日志先写入有界队列，再由单独的goroutine写入ws
+// Close之后的日志同步写入ws
+type asyncWriter struct {
+	ws          zapcore.WriteSyncer
+	policy      OverflowPolicy
+	queue       chan asyncEntry
+	done        chan struct{}
+	syncTimeout time.Duration // Sync等待队列写入的超时时间
+
+	mu     sync.RWMutex // Close时加写锁，防止向已关闭的队列写入
+	closed bool
+}
+
+// newAsyncWriter 创建asyncWriter并启动写日志goroutine
+func newAsyncWriter(ws zapcore.WriteSyncer, queueSize int, policy OverflowPolicy) *asyncWriter {
+	if queueSize <= 0 {
+		queueSize = 1
+	}
+
+	a := &asyncWriter{
+		ws:          ws,
+		policy:      policy,
+		queue:       make(chan asyncEntry, queueSize),
+		done:        make(chan struct{}),
+		syncTimeout: defaultAsyncSyncTimeout,
+	}
+
+	go a.run()
+	return a
+}
+
+// run 将队列中的日志写入ws
+func (a *asyncWriter) run() {
+	defer close(a.done)
+	for e := range a.queue {
+		if e.flushed != nil {
+			close(e.flushed)
+			continue
+		}
+
+		_, _ = a.ws.Write(e.p)
+	}
+}
+
+// Write implements zapcore.WriteSyncer，p会被zap复用，这里需要复制一份
+func (a *asyncWriter) Write(p []byte) (int, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		return a.ws.Write(p)
+	}
+
+	e := asyncEntry{p: append([]byte(nil), p...)}
+	switch a.policy {
+	case OverflowDrop:
+		select {
+		case a.queue <- e:
+		default:
+			logDropped.With(dropReasonOverflow, "").Inc()
+		}
+	case OverflowDropOldest:
+		a.dropOldest(e)
+	default:
+		a.queue <- e
+	}
+
+	return len(p), nil
+}
+
+// dropOldest 队列满时丢弃最早的日志，Sync标记不会被丢弃
+func (a *asyncWriter) dropOldest(e asyncEntry) {
+	for {
+		select {
+		case a.queue <- e:
+			return
+		default:
+		}
+
+		select {
+		case old := <-a.queue:
+			if old.flushed == nil {
+				logDropped.With(dropReasonOverflow, "").Inc()
+				continue
+			}
+
+			// 放回Sync标记，丢弃新日志
+			select {
+			case a.queue <- old:
+			default:
+				close(old.flushed)
+			}
+
+			logDropped.With(dropReasonOverflow, "").Inc()
+			return
+		default:
+		}
+	}
+}
+
+// Sync implements zapcore.WriteSyncer，等待队列中已有的日志写入后调用ws.Sync
+// 队列满或ws写入阻塞时，超过syncTimeout返回errAsyncSyncTimeout，不会一直阻塞
+func (a *asyncWriter) Sync() error {
+	a.mu.RLock()
+	if a.closed {
+		a.mu.RUnlock()
+		return a.ws.Sync()
+	}
+
+	timer := time.NewTimer(a.syncTimeout)
+	defer timer.Stop()
+
+	flushed := make(chan struct{})
+	select {
+	case a.queue <- asyncEntry{flushed: flushed}:
+		a.mu.RUnlock()
+	case <-timer.C:
+		a.mu.RUnlock()
+		return errAsyncSyncTimeout
+	}
+
+	select {
+	case <-flushed:
+	case <-timer.C:
+		return errAsyncSyncTimeout
+	}
+
+	return a.ws.Sync()
+}
+
+// Close 关闭队列，等待所有日志写入后调用ws.Sync
+func (a *asyncWriter) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+
+	a.closed = true
+	close(a.queue)
+	a.mu.Unlock()
+
+	<-a.done
+	return a.ws.Sync()
+}
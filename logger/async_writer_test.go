@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingWriter block the first write until release is closed
+type blockingWriter struct {
+	mu       sync.Mutex
+	lines    []string
+	started  chan struct{}
+	release  chan struct{}
+	blocking bool
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	if !w.blocking {
+		w.blocking = true
+		close(w.started)
+		<-w.release
+	}
+
+	w.mu.Lock()
+	w.lines = append(w.lines, string(p))
+	w.mu.Unlock()
+	return len(p), nil
+}
+
+func (w *blockingWriter) Sync() error {
+	return nil
+}
+
+// TestAsyncWriterOverflow test overflow policies of async writer.
+func TestAsyncWriterOverflow(t *testing.T) {
+	for policy, want := range map[OverflowPolicy][]string{
+		OverflowDrop:       {"0", "1", "2"},
+		OverflowDropOldest: {"0", "3", "4"},
+	} {
+		w := &blockingWriter{started: make(chan struct{}), release: make(chan struct{})}
+		a := newAsyncWriter(w, 2, policy)
+		_, _ = a.Write([]byte("0"))
+		<-w.started
+		for _, s := range []string{"1", "2", "3", "4"} {
+			_, _ = a.Write([]byte(s))
+		}
+
+		close(w.release)
+		if err := a.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		_, _ = a.Write([]byte("5"))
+		if want = append(want, "5"); !reflect.DeepEqual(w.lines, want) {
+			t.Fatalf("policy %d lines: %v", policy, w.lines)
+		}
+	}
+}
+
+// TestAsyncWriterSyncTimeout test Sync returns when the writer is blocked,
+// both with a full queue and with the flush marker queued.
+func TestAsyncWriterSyncTimeout(t *testing.T) {
+	for _, lines := range [][]string{{"0", "1"}, {"0"}} {
+		w := &blockingWriter{started: make(chan struct{}), release: make(chan struct{})}
+		a := newAsyncWriter(w, 1, OverflowDrop)
+		a.syncTimeout = 20 * time.Millisecond
+		_, _ = a.Write([]byte(lines[0]))
+		<-w.started
+		for _, s := range lines[1:] {
+			_, _ = a.Write([]byte(s))
+		}
+
+		if err := a.Sync(); err != errAsyncSyncTimeout {
+			t.Fatalf("sync with %d queued lines error: %v", len(lines)-1, err)
+		}
+
+		close(w.release)
+		if err := a.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(w.lines, lines) {
+			t.Fatalf("lines: %v", w.lines)
+		}
+	}
+}
+
+// TestAsyncLogger test all buffered lines are written after Close.
+func TestAsyncLogger(t *testing.T) {
+	dir := t.TempDir()
+	logEntry = New(WithLogDir(dir), WithLogFilename("async.log"), WithWriteToFile(true), WithStdout(false),
+		WithAsync(16, OverflowBlock))
+	defer func() { logEntry = nil }()
+
+	ctx := context.Background()
+	for i := 0; i < 100; i++ {
+		Info(ctx, "async line", "i", i)
+	}
+
+	if err := Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if lines := readLogLines(t, filepath.Join(dir, "async.log")); len(lines) != 100 {
+		t.Fatalf("log lines after sync: %d", len(lines))
+	}
+
+	Info(ctx, "last line")
+	if err := Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if lines := readLogLines(t, filepath.Join(dir, "async.log")); len(lines) != 101 {
+		t.Fatalf("log lines after close: %d", len(lines))
+	}
+}
@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"fmt"
+	"time"
+)
+
+// Config 日志配置，可以从配置文件的logger段落读取，未设置的字段使用默认值
+/**
+logger:
+  level: info
+  dir: ./logs
+  filename: app.log
+  max_size: 200
+  max_age: 3
+  max_backups: 10
+  rotate_time: 24h
+  compress: false
+  json: true
+  stdout: false
+  color: false
+  caller: true
+  modules:
+    grpc: warn
+*/
+type Config struct {
+	LevelConfig `mapstructure:",squash"`
+
+	Dir        string        `mapstructure:"dir"`         // 日志目录
+	Filename   string        `mapstructure:"filename"`    // 日志文件名
+	MaxSize    int           `mapstructure:"max_size"`    // 日志大小，单位MB
+	MaxAge     int           `mapstructure:"max_age"`     // 日志保留天数
+	MaxBackups int           `mapstructure:"max_backups"` // 保留的旧日志文件数
+	RotateTime time.Duration `mapstructure:"rotate_time"` // 按时间切割的间隔，比如1h,24h
+	Compress   *bool         `mapstructure:"compress"`    // 是否压缩
+	JSON       *bool         `mapstructure:"json"`        // 是否json格式化
+	Stdout     *bool         `mapstructure:"stdout"`      // 是否输出到终端
+	Color      *bool         `mapstructure:"color"`       // 是否日志染色
+	Caller     *bool         `mapstructure:"caller"`      // 是否输出文件名和行号
+	Sinks      []Sink        `mapstructure:"sinks"`       // 多个日志输出
+}
+
+// Options 将配置转换为Option，只转换设置了的字段
+func (c *Config) Options() ([]Option, error) {
+	var opts []Option
+	if c.Level != "" {
+		level, err := parseLevel(c.Level)
+		if err != nil {
+			return nil, fmt.Errorf("logger level error: %w", err)
+		}
+
+		opts = append(opts, WithLogLevel(level))
+	}
+
+	for module, l := range c.Modules {
+		level, err := parseLevel(l)
+		if err != nil {
+			return nil, fmt.Errorf("logger module %s level error: %w", module, err)
+		}
+
+		opts = append(opts, WithModuleLevel(module, level))
+	}
+
+	if c.Dir != "" {
+		opts = append(opts, WithLogDir(c.Dir))
+	}
+
+	if c.Filename != "" {
+		opts = append(opts, WithLogFilename(c.Filename))
+	}
+
+	if c.MaxSize > 0 {
+		opts = append(opts, WithMaxSize(c.MaxSize))
+	}
+
+	if c.MaxAge > 0 {
+		opts = append(opts, WithMaxAge(c.MaxAge))
+	}
+
+	if c.MaxBackups > 0 {
+		opts = append(opts, WithMaxBackups(c.MaxBackups))
+	}
+
+	if c.RotateTime > 0 {
+		opts = append(opts, WithRotateTime(c.RotateTime))
+	}
+
+	if c.Compress != nil {
+		opts = append(opts, WithCompress(*c.Compress))
+	}
+
+	if c.JSON != nil {
+		opts = append(opts, WithJsonFormat(*c.JSON))
+	}
+
+	if c.Stdout != nil {
+		opts = append(opts, WithStdout(*c.Stdout))
+	}
+
+	if c.Color != nil {
+		opts = append(opts, WithEnableColor(*c.Color))
+	}
+
+	if c.Caller != nil {
+		opts = append(opts, WithAddCaller(*c.Caller))
+	}
+
+	if len(c.Sinks) > 0 {
+		opts = append(opts, WithSinks(c.Sinks...))
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ContextExtractor 从ctx中提取日志字段，追加到fields并返回
+type ContextExtractor func(ctx context.Context, fields []zap.Field) []zap.Field
+
+// contextExtractor 注册的ctx字段提取器
+type contextExtractor struct {
+	name    string
+	extract ContextExtractor
+}
+
+// contextExtractors 按注册顺序提取ctx字段，写时复制
+var contextExtractors = struct {
+	sync.Mutex
+	value atomic.Value // []contextExtractor
+}{}
+
+func init() {
+	contextExtractors.value.Store([]contextExtractor(nil))
+
+	// 内置字段
+	RegisterContextExtractor(LocalTime.String(), extractLocalTime)
+	RegisterContextField(ReqClientIP, ReqClientIP.String())
+	RegisterContextField(RequestMethod, RequestMethod.String())
+	RegisterContextField(RequestURI, RequestURI.String())
+	RegisterContextField(TraceID, TraceID.String())
+	RegisterContextField(SpanID, SpanID.String())
+}
+
+// RegisterContextField 注册ctx字段，ctx.Value(key)存在时以name记录到日志中
+// 比如 logger.RegisterContextField(TenantIDKey{}, "tenant_id")
+func RegisterContextField(key interface{}, name string) {
+	RegisterContextExtractor(name, func(ctx context.Context, fields []zap.Field) []zap.Field {
+		if v := ctx.Value(key); v != nil {
+			fields = append(fields, zap.Any(name, v))
+		}
+
+		return fields
+	})
+}
+
+// RegisterContextExtractor 注册ctx字段提取器，name相同时替换已注册的提取器
+func RegisterContextExtractor(name string, fn ContextExtractor) {
+	contextExtractors.Lock()
+	defer contextExtractors.Unlock()
+
+	old := contextExtractors.value.Load().([]contextExtractor)
+	extractors := make([]contextExtractor, 0, len(old)+1)
+	replaced := false
+	for _, e := range old {
+		if e.name == name {
+			e.extract = fn
+			replaced = true
+		}
+
+		extractors = append(extractors, e)
+	}
+
+	if !replaced {
+		extractors = append(extractors, contextExtractor{name: name, extract: fn})
+	}
+
+	contextExtractors.value.Store(extractors)
+}
+
+// UnregisterContextField 删除ctx字段提取器，内置字段也可以删除
+func UnregisterContextField(name string) {
+	contextExtractors.Lock()
+	defer contextExtractors.Unlock()
+
+	old := contextExtractors.value.Load().([]contextExtractor)
+	extractors := make([]contextExtractor, 0, len(old))
+	for _, e := range old {
+		if e.name != name {
+			extractors = append(extractors, e)
+		}
+	}
+
+	contextExtractors.value.Store(extractors)
+}
+
+// extractContextFields 使用所有提取器提取ctx字段
+func extractContextFields(ctx context.Context, fields []zap.Field) []zap.Field {
+	for _, e := range contextExtractors.value.Load().([]contextExtractor) {
+		fields = e.extract(ctx, fields)
+	}
+
+	return fields
+}
+
+// extractLocalTime 记录请求本地时间，ctx中不存在时使用当前时间
+func extractLocalTime(ctx context.Context, fields []zap.Field) []zap.Field {
+	if curTime := ctx.Value(LocalTime); curTime != nil {
+		return append(fields, zap.Any(LocalTime.String(), curTime))
+	}
+
+	return append(fields, zap.String(LocalTime.String(), time.Now().Format(tmFmtWithMS)))
+}
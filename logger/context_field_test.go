@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+type tenantKey struct{}
+
+// TestContextField test custom ctx fields and extractors.
+func TestContextField(t *testing.T) {
+	RegisterContextField(tenantKey{}, "tenant_id")
+	RegisterContextExtractor("user", func(ctx context.Context, fields []zap.Field) []zap.Field {
+		return append(fields, zap.Int64("user_id", 42))
+	})
+	defer UnregisterContextField("tenant_id")
+	defer UnregisterContextField("user")
+
+	dir := t.TempDir()
+	l := New(WithLogDir(dir), WithLogFilename("ctx.log"), WithWriteToFile(true), WithStdout(false))
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	ctx = context.WithValue(ctx, ReqClientIP, "10.0.0.1")
+	l.Info(ctx, "with tenant")
+
+	UnregisterContextField(ReqClientIP.String())
+	defer RegisterContextField(ReqClientIP, ReqClientIP.String())
+	l.Info(ctx, "without client ip")
+
+	lines := readLogLines(t, filepath.Join(dir, "ctx.log"))
+	if len(lines) != 2 || lines[0]["tenant_id"] != "acme" || lines[0]["user_id"] != float64(42) ||
+		lines[0][ReqClientIP.String()] != "10.0.0.1" {
+		t.Fatalf("log lines: %v", lines)
+	}
+
+	if _, ok := lines[1][ReqClientIP.String()]; ok || lines[1][LocalTime.String()] == nil {
+		t.Fatalf("log line without client ip: %v", lines[1])
+	}
+}
@@ -1,5 +1,7 @@
 package logger
 
+import "context"
+
 // CtxKey ctx key struct.
 type CtxKey struct {
 	Name string
@@ -31,4 +33,34 @@ var (
 
 	// Fullstack full stack
 	Fullstack = CtxKey{"full_stack"}
+
+	// TraceID trace_id of the current span
+	TraceID = CtxKey{"trace_id"}
+
+	// SpanID span_id of the current span
+	SpanID = CtxKey{"span_id"}
 )
+
+// mergedContext ctx中不存在的值从fallback中查找
+type mergedContext struct {
+	context.Context
+	fallback context.Context
+}
+
+// Value implements context.Context
+func (m mergedContext) Value(key interface{}) interface{} {
+	if v := m.Context.Value(key); v != nil {
+		return v
+	}
+
+	return m.fallback.Value(key)
+}
+
+// mergeContext 合并ctx和fallback，ctx为nil时返回fallback
+func mergeContext(ctx, fallback context.Context) context.Context {
+	if ctx == nil || ctx == fallback {
+		return fallback
+	}
+
+	return mergedContext{Context: ctx, fallback: fallback}
+}
@@ -3,6 +3,7 @@ package logger
 import (
 	"context"
 	"runtime/debug"
+	"sync"
 
 	"go.uber.org/zap"
 )
@@ -11,6 +12,9 @@ var (
 	// logEntry default logger entry.
 	logEntry Logger
 
+	// logEntryMu 保护 DefaultLogger 懒加载创建logEntry
+	logEntryMu sync.Mutex
+
 	// DefaultLogDir default log dir.
 	DefaultLogDir = "./logs"
 
@@ -21,6 +25,15 @@ var (
 // Default 默认zap logger对象
 // WithWriteToFile 默认写入文件中，如需要在终端输入使用 logger.WithStdout(true) 开启
 func Default(opts ...Option) {
+	l := New(defaultOptions(opts...)...)
+
+	logEntryMu.Lock()
+	logEntry = l
+	logEntryMu.Unlock()
+}
+
+// defaultOptions Default 使用的默认配置，opts可以覆盖默认配置
+func defaultOptions(opts ...Option) []Option {
 	options := []Option{
 		WithLogDir(DefaultLogDir),       // 日志目录
 		WithLogFilename(DefaultLogFile), // 日志文件名，默认zap.log
@@ -39,7 +52,84 @@ func Default(opts ...Option) {
 		options = append(options, opts...)
 	}
 
-	logEntry = New(options...)
+	return options
+}
+
+// DefaultLogger return the default logger entry,its methods can be called directly
+// if Default has not been called,it will be created once with default options.
+func DefaultLogger() Logger {
+	logEntryMu.Lock()
+	if logEntry == nil {
+		logEntry = New(defaultOptions()...)
+	}
+	l := logEntry
+	logEntryMu.Unlock()
+
+	return directLogger(l)
+}
+
+// syncCloser 支持Sync和Close的logger
+type syncCloser interface {
+	Sync() error
+	Close() error
+}
+
+// Sync 将默认logger缓冲的日志写入文件
+func Sync() error {
+	if sc, ok := logEntry.(syncCloser); ok {
+		return sc.Sync()
+	}
+
+	return nil
+}
+
+// Close 写入默认logger缓冲的日志，并关闭日志文件，一般在程序退出前调用
+func Close() error {
+	if sc, ok := logEntry.(syncCloser); ok {
+		return sc.Close()
+	}
+
+	return nil
+}
+
+// rotator 支持日志切割的logger
+type rotator interface {
+	Rotate() error
+}
+
+// Rotate 切割默认logger的日志文件，msa engine收到SIGHUP或SIGUSR1信号时会自动调用
+func Rotate() error {
+	if r, ok := logEntry.(rotator); ok {
+		return r.Rotate()
+	}
+
+	return nil
+}
+
+// With 返回默认logger绑定了fields的子logger
+func With(fields ...interface{}) Logger {
+	return directLogger(logEntry).With(fields...)
+}
+
+// Named 返回默认logger指定名称的子logger
+func Named(name string) Logger {
+	return directLogger(logEntry).Named(name)
+}
+
+// WithContext 返回默认logger绑定了ctx的子logger
+func WithContext(ctx context.Context) Logger {
+	return directLogger(logEntry).WithContext(ctx)
+}
+
+// directLogger 包函数比直接调用logger多一层调用栈，返回的子logger需要减少一层callerSkip
+func directLogger(l Logger) Logger {
+	if z, ok := l.(*zapLogWriter); ok {
+		c := z.derive()
+		c.fLogger = z.fLogger.WithOptions(zap.AddCallerSkip(-1))
+		return c
+	}
+
+	return l
 }
 
 // Debug debug级别日志
@@ -0,0 +1,243 @@
+package logger
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LevelConfig 日志级别配置，可以通过配置文件的logger段落热更新
+/**
+logger:
+  level: info
+  modules:
+    grpc: warn
+    order.repo: debug
+*/
+type LevelConfig struct {
+	Level   string            `mapstructure:"level"`   // 全局日志级别
+	Modules map[string]string `mapstructure:"modules"` // 模块日志级别，key为logger name
+}
+
+// levelController 运行时日志级别控制接口
+type levelController interface {
+	SetLevel(level zapcore.Level)
+	Level() zapcore.Level
+	SetModuleLevels(levels map[string]zapcore.Level)
+	ModuleLevels() map[string]zapcore.Level
+}
+
+// SetLevel 运行时修改默认logger的日志级别，比如debug,info,warn,error
+func SetLevel(level string) error {
+	l, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	lc, err := defaultLevelController()
+	if err != nil {
+		return err
+	}
+
+	lc.SetLevel(l)
+	return nil
+}
+
+// GetLevel 返回默认logger的日志级别，如果默认logger不支持则返回空字符串
+func GetLevel() string {
+	if lc, err := defaultLevelController(); err == nil {
+		return lc.Level().String()
+	}
+
+	return ""
+}
+
+// SetModuleLevel 运行时修改默认logger某个模块的日志级别，module为logger name
+// 模块级别对子模块同样生效，比如order对order.repo生效，level为空时删除该模块的级别
+func SetModuleLevel(module string, level string) error {
+	lc, err := defaultLevelController()
+	if err != nil {
+		return err
+	}
+
+	levels := lc.ModuleLevels()
+	if level == "" {
+		delete(levels, module)
+	} else {
+		l, err := parseLevel(level)
+		if err != nil {
+			return err
+		}
+
+		levels[module] = l
+	}
+
+	lc.SetModuleLevels(levels)
+	return nil
+}
+
+// GetModuleLevels 返回默认logger所有模块的日志级别
+func GetModuleLevels() map[string]string {
+	levels := make(map[string]string)
+	if lc, err := defaultLevelController(); err == nil {
+		for module, l := range lc.ModuleLevels() {
+			levels[module] = l.String()
+		}
+	}
+
+	return levels
+}
+
+// ApplyLevelConfig 应用日志级别配置，模块级别会被整体替换
+// 适用于配置文件热更新后刷新日志级别
+func ApplyLevelConfig(c LevelConfig) error {
+	lc, err := defaultLevelController()
+	if err != nil {
+		return err
+	}
+
+	var global zapcore.Level
+	if c.Level != "" {
+		if global, err = parseLevel(c.Level); err != nil {
+			return err
+		}
+	}
+
+	levels := make(map[string]zapcore.Level, len(c.Modules))
+	for module, level := range c.Modules {
+		if levels[module], err = parseLevel(level); err != nil {
+			return err
+		}
+	}
+
+	if c.Level != "" {
+		lc.SetLevel(global)
+	}
+
+	lc.SetModuleLevels(levels)
+	return nil
+}
+
+// defaultLevelController 返回默认logger的levelController
+func defaultLevelController() (levelController, error) {
+	lc, ok := logEntry.(levelController)
+	if !ok {
+		return nil, errors.New("default logger does not support level change")
+	}
+
+	return lc, nil
+}
+
+// parseLevel 解析日志级别字符串
+func parseLevel(level string) (zapcore.Level, error) {
+	var l zapcore.Level
+	err := l.UnmarshalText([]byte(level))
+	return l, err
+}
+
+// levelRegistry 全局日志级别和模块日志级别
+type levelRegistry struct {
+	global  zap.AtomicLevel
+	mu      sync.Mutex   // 修改模块级别时加锁
+	modules atomic.Value // map[string]zapcore.Level，写时复制
+	min     int32        // 所有模块级别中的最低级别
+}
+
+// newLevelRegistry 创建levelRegistry
+func newLevelRegistry(level zapcore.Level) *levelRegistry {
+	r := &levelRegistry{global: zap.NewAtomicLevelAt(level)}
+	r.modules.Store(map[string]zapcore.Level{})
+	return r
+}
+
+// setModules 替换所有模块级别
+func (r *levelRegistry) setModules(levels map[string]zapcore.Level) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	modules := make(map[string]zapcore.Level, len(levels))
+	min := zapcore.FatalLevel + 1
+	for module, l := range levels {
+		modules[module] = l
+		if l < min {
+			min = l
+		}
+	}
+
+	atomic.StoreInt32(&r.min, int32(min))
+	r.modules.Store(modules)
+}
+
+// getModules 返回所有模块级别的副本
+func (r *levelRegistry) getModules() map[string]zapcore.Level {
+	modules := r.modules.Load().(map[string]zapcore.Level)
+	levels := make(map[string]zapcore.Level, len(modules))
+	for module, l := range modules {
+		levels[module] = l
+	}
+
+	return levels
+}
+
+// Enabled 全局级别或任一模块级别开启时返回true，具体由enabledFor决定
+func (r *levelRegistry) Enabled(l zapcore.Level) bool {
+	if r.global.Enabled(l) {
+		return true
+	}
+
+	modules := r.modules.Load().(map[string]zapcore.Level)
+	return len(modules) > 0 && l >= zapcore.Level(atomic.LoadInt32(&r.min))
+}
+
+// enabledFor 根据logger name查找最近的模块级别，没有找到使用全局级别
+func (r *levelRegistry) enabledFor(name string, l zapcore.Level) bool {
+	modules := r.modules.Load().(map[string]zapcore.Level)
+	for len(modules) > 0 && name != "" {
+		if ml, ok := modules[name]; ok {
+			return ml.Enabled(l)
+		}
+
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+
+		name = name[:i]
+	}
+
+	return r.global.Enabled(l)
+}
+
+// levelCore 根据logger name过滤日志级别的core
+type levelCore struct {
+	zapcore.Core
+	levels *levelRegistry
+}
+
+// Enabled implements zapcore.LevelEnabler
+func (c *levelCore) Enabled(l zapcore.Level) bool {
+	return c.levels.Enabled(l)
+}
+
+// With implements zapcore.Core
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), levels: c.levels}
+}
+
+// Check implements zapcore.Core
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.levels.enabledFor(ent.LoggerName, ent.Level) {
+		return ce
+	}
+
+	return c.Core.Check(ent, ce)
+}
+
+// allLevels 所有级别都开启，由levelCore过滤
+var allLevels = zap.LevelEnablerFunc(func(zapcore.Level) bool {
+	return true
+})
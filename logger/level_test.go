@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// TestModuleLevel test global and module levels can be changed at runtime.
+func TestModuleLevel(t *testing.T) {
+	dir := t.TempDir()
+	opts := []Option{WithLogDir(dir), WithLogFilename("level.log"), WithWriteToFile(true), WithStdout(false)}
+	app := New(opts...)
+	repo := New(append(opts, WithName("order.repo"), WithModuleLevel("order", zapcore.DebugLevel))...)
+
+	ctx := context.Background()
+	app.Debug(ctx, "app debug")
+	repo.Debug(ctx, "repo debug")
+
+	logEntry = repo
+	defer func() { logEntry = nil }()
+
+	if err := ApplyLevelConfig(LevelConfig{Level: "debug", Modules: map[string]string{"order.repo": "error"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	repo.Warn(ctx, "repo warn")
+	repo.Error(ctx, "repo error")
+	if err := SetModuleLevel("order.repo", ""); err != nil || GetLevel() != "debug" || len(GetModuleLevels()) != 0 {
+		t.Fatalf("module levels: %v error: %v", GetModuleLevels(), err)
+	}
+
+	repo.Debug(ctx, "repo debug again")
+	if err := SetLevel("verbose"); err == nil {
+		t.Fatal("invalid level is accepted")
+	}
+
+	lines := readLogLines(t, filepath.Join(dir, "level.log"))
+	var msgs []string
+	for _, line := range lines {
+		msgs = append(msgs, line["msg"].(string))
+	}
+
+	if len(msgs) != 3 || msgs[0] != "repo debug" || msgs[1] != "repo error" || msgs[2] != "repo debug again" {
+		t.Fatalf("log messages: %v", msgs)
+	}
+}
@@ -32,4 +32,13 @@ type Logger interface {
 
 	// Fatal 抛出致命错误，然后退出程序
 	Fatal(ctx context.Context, msg string, fields ...interface{})
+
+	// With 返回绑定了fields的子logger，fields格式与日志方法相同
+	With(fields ...interface{}) Logger
+
+	// Named 返回指定名称的子logger，名称以.连接，比如order.repo，可用于模块日志级别
+	Named(name string) Logger
+
+	// WithContext 返回绑定了ctx的子logger，日志方法的ctx中不存在的值从绑定的ctx中查找
+	WithContext(ctx context.Context) Logger
 }
@@ -4,10 +4,13 @@ import (
 	"context"
 	"log"
 	"runtime/debug"
+	"sync"
 	"testing"
 	"time"
 
 	"go.uber.org/zap"
+
+	"github.com/go-god/msa/idgen"
 )
 
 // TestLogger test logger.
@@ -169,3 +172,30 @@ func BenchmarkRndUUID(b *testing.B) {
 		log.Println("rnd uuid: ", s)
 	}
 }
+
+// TestSetIDGenerator 测试并发设置和使用request_id generator
+func TestSetIDGenerator(t *testing.T) {
+	defer SetIDGenerator(idgen.UUIDv4)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			SetIDGenerator(idgen.GeneratorFunc(func() string { return "fixed" }))
+			SetIDGenerator(idgen.ULID)
+		}()
+		go func() {
+			defer wg.Done()
+			if NewRequestID() == "" {
+				t.Error("request id is empty")
+			}
+		}()
+	}
+	wg.Wait()
+
+	SetIDGenerator(idgen.GeneratorFunc(func() string { return "fixed" }))
+	if id := NewRequestID(); id != "fixed" {
+		t.Fatalf("request id: %s", id)
+	}
+}
@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// TestDerivedLogger test With, Named and WithContext.
+func TestDerivedLogger(t *testing.T) {
+	dir := t.TempDir()
+	logEntry = New(WithLogDir(dir), WithLogFilename("with.log"), WithWriteToFile(true), WithStdout(false),
+		WithModuleLevel("order.repo", zapcore.WarnLevel))
+	defer func() { logEntry = nil }()
+
+	bound := context.WithValue(context.Background(), XRequestID, "bound-id")
+	l := Named("order").With("component", "order", map[string]interface{}{"version": 2}).WithContext(bound)
+	l.Info(context.Background(), "bound ctx")
+	l.Info(context.WithValue(context.Background(), XRequestID, "call-id"), "call ctx")
+
+	repo := l.Named("repo")
+	repo.Info(nil, "repo info")
+	repo.Warn(nil, "repo warn")
+
+	lines := readLogLines(t, filepath.Join(dir, "with.log"))
+	if len(lines) != 3 {
+		t.Fatalf("log lines: %v", lines)
+	}
+
+	key := XRequestID.String()
+	if lines[0][key] != "bound-id" || lines[1][key] != "call-id" || lines[2][key] != "bound-id" {
+		t.Fatalf("request ids: %v %v %v", lines[0][key], lines[1][key], lines[2][key])
+	}
+
+	if lines[0]["component"] != "order" || lines[0]["version"] != float64(2) || lines[2]["logger"] != "order.repo" {
+		t.Fatalf("bound fields: %v", lines[2])
+	}
+}
+
+// TestDefaultLogger test the default logger is created once and reports the direct caller.
+func TestDefaultLogger(t *testing.T) {
+	dir, logDir := t.TempDir(), DefaultLogDir
+	DefaultLogDir = dir
+	defer func() { DefaultLogDir, logEntry = logDir, nil }()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			DefaultLogger()
+		}()
+	}
+	wg.Wait()
+
+	entry := logEntry
+	DefaultLogger().Info(context.Background(), "lazy")
+	if logEntry != entry {
+		t.Fatal("default logger created more than once")
+	}
+
+	_ = Close()
+	Default(WithAddCaller(true))
+	DefaultLogger().Info(context.Background(), "direct")
+	_, file, line, _ := runtime.Caller(0)
+	Info(context.Background(), "package")
+	if err := Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := readLogLines(t, filepath.Join(dir, DefaultLogFile))
+	if len(lines) != 3 {
+		t.Fatalf("log lines: %v", lines)
+	}
+
+	want := []string{file + ":" + strconv.Itoa(line-1), file + ":" + strconv.Itoa(line+1)}
+	if lines[1]["caller_line"] != want[0] || lines[2]["caller_line"] != want[1] {
+		t.Fatalf("caller: %v %v, want %v", lines[1]["caller_line"], lines[2]["caller_line"], want)
+	}
+}
+
+// TestDerivedLoggerClose test closing derived loggers keeps the resources of the root logger.
+func TestDerivedLoggerClose(t *testing.T) {
+	dir := t.TempDir()
+	root := New(WithLogDir(dir), WithLogFilename("close.log"), WithWriteToFile(true), WithStdout(false),
+		WithAsync(16, OverflowBlock), WithSampling(time.Minute, 100, 0)).(*zapLogWriter)
+	defer root.Close()
+
+	for _, l := range []Logger{root.With("k", "v"), root.Named("child"), root.WithContext(context.Background())} {
+		if err := l.(*zapLogWriter).Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case <-root.dropStats.done:
+		t.Fatal("drop summary of root logger is stopped by derived logger")
+	default:
+	}
+
+	for _, aw := range root.asyncWriters {
+		aw.mu.RLock()
+		closed := aw.closed
+		aw.mu.RUnlock()
+		if closed {
+			t.Fatal("async writer of root logger is closed by derived logger")
+		}
+	}
+}
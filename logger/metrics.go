@@ -0,0 +1,19 @@
+package logger
+
+import (
+	"go.uber.org/zap/zapcore"
+
+	"github.com/go-god/msa/metrics"
+)
+
+// logLines log lines counter by level
+var logLines = metrics.NewCounter("msa_log_lines_total", "Total log lines by level.", "level")
+
+// logDropped log lines dropped by sampling or rate limit
+var logDropped = metrics.NewCounter("msa_log_dropped_total", "Total dropped log lines by reason and level.", "reason", "level")
+
+// countLogLine zap hook to count log lines
+func countLogLine(entry zapcore.Entry) error {
+	logLines.With(entry.Level.String()).Inc()
+	return nil
+}
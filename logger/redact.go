@@ -0,0 +1,398 @@
+package logger
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RedactMask 敏感字段脱敏后的值
+const RedactMask = "******"
+
+// redactTag 结构体字段tag为 log:"redact" 时脱敏
+const redactTag = "redact"
+
+// redactMaxDepth 脱敏时遍历map,struct,slice的最大深度
+const redactMaxDepth = 10
+
+// DefaultRedactKeys 默认的敏感字段，字段名按分隔符和驼峰拆分后包含其中之一(不区分大小写)时脱敏
+// 比如 db_password,userPassword,X-Api-Key 脱敏，tokenizer_version 不脱敏
+var DefaultRedactKeys = []string{
+	"password", "passwd", "pwd", "secret", "token", "authorization",
+	"api_key", "apikey", "private_key", "access_key", "credential", "credentials", "cookie",
+}
+
+// ValueMask 按值脱敏，Pattern匹配到的内容由Replace替换
+type ValueMask struct {
+	Name    string
+	Pattern *regexp.Regexp
+	Replace func(match string) string
+}
+
+var (
+	// CreditCardMask 信用卡号脱敏，通过Luhn校验的13-19位数字只保留后4位
+	CreditCardMask = ValueMask{
+		Name:    "credit_card",
+		Pattern: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		Replace: maskCreditCard,
+	}
+
+	// EmailMask 邮箱脱敏，只保留第一个字符和域名，比如 j***@example.com
+	EmailMask = ValueMask{
+		Name:    "email",
+		Pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+		Replace: maskEmail,
+	}
+
+	// DefaultValueMasks 常用的按值脱敏规则，默认不开启，通过 WithRedactValues(DefaultValueMasks...) 开启
+	// 按值脱敏可能误伤，比如通过Luhn校验的雪花ID和纳秒时间戳会被当作卡号
+	DefaultValueMasks = []ValueMask{CreditCardMask, EmailMask}
+)
+
+// redactor 在日志编码前对敏感字段脱敏
+type redactor struct {
+	keys  []string
+	masks []ValueMask
+}
+
+// newRedactor 创建redactor，keys转换为 keySegments 格式
+func newRedactor(keys []string, masks []ValueMask) *redactor {
+	r := &redactor{masks: masks}
+	for _, k := range keys {
+		r.keys = append(r.keys, keySegments(k))
+	}
+
+	return r
+}
+
+// fields 对所有字段脱敏
+func (r *redactor) fields(fields []zap.Field) []zap.Field {
+	for i := range fields {
+		fields[i] = r.field(fields[i])
+	}
+
+	return fields
+}
+
+// field 字段名敏感时整体脱敏，否则对字符串,error,fmt.Stringer和map,struct,slice的值脱敏
+// zapcore.ObjectMarshaler等自定义编码的值不脱敏
+func (r *redactor) field(f zap.Field) zap.Field {
+	if r.sensitiveKey(f.Key) {
+		return zap.String(f.Key, RedactMask)
+	}
+
+	switch f.Type {
+	case zapcore.StringType:
+		f.String = r.maskString(f.String)
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok && len(r.masks) > 0 {
+			if msg := r.maskString(err.Error()); msg != err.Error() {
+				return zap.String(f.Key, msg)
+			}
+		}
+	case zapcore.StringerType:
+		if st, ok := f.Interface.(fmt.Stringer); ok && len(r.masks) > 0 {
+			if str := r.maskString(st.String()); str != st.String() {
+				return zap.String(f.Key, str)
+			}
+		}
+	case zapcore.ArrayMarshalerType:
+		// zap.Any([]string)等基础类型的slice
+		if v := reflect.ValueOf(f.Interface); v.Kind() == reflect.Slice {
+			if val, changed := r.sliceValue(v, 0); changed {
+				return zap.Any(f.Key, val)
+			}
+		}
+	case zapcore.ReflectType:
+		if v, changed := r.value(reflect.ValueOf(f.Interface), 0); changed {
+			f.Interface = v
+		}
+	}
+
+	return f
+}
+
+// sensitiveKey 字段名拆分后的词包含敏感字段之一
+func (r *redactor) sensitiveKey(key string) bool {
+	if len(r.keys) == 0 {
+		return false
+	}
+
+	key = keySegments(key)
+	for _, k := range r.keys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// keySegments 字段名按非字母数字字符和驼峰拆分为小写的词，以_连接，首尾也加上_
+// 比如 userAPIKey 转换为 _user_api_key_，X-Api-Key 转换为 _x_api_key_
+func keySegments(key string) string {
+	b := make([]byte, 1, len(key)+8)
+	b[0] = '_'
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case isUpper(c):
+			// 小写或数字后的大写，或者连续大写中下一个是小写的大写，是一个新词的开始
+			if i > 0 && b[len(b)-1] != '_' && (isLower(key[i-1]) || isDigit(key[i-1]) ||
+				(i+1 < len(key) && isLower(key[i+1]))) {
+				b = append(b, '_')
+			}
+
+			b = append(b, c+'a'-'A')
+		case isLower(c) || isDigit(c):
+			b = append(b, c)
+		case b[len(b)-1] != '_':
+			b = append(b, '_')
+		}
+	}
+
+	if b[len(b)-1] != '_' {
+		b = append(b, '_')
+	}
+
+	return string(b)
+}
+
+func isUpper(c byte) bool {
+	return 'A' <= c && c <= 'Z'
+}
+
+func isLower(c byte) bool {
+	return 'a' <= c && c <= 'z'
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// maskString 按值脱敏
+func (r *redactor) maskString(s string) string {
+	for _, m := range r.masks {
+		s = m.Pattern.ReplaceAllStringFunc(s, m.Replace)
+	}
+
+	return s
+}
+
+// value 对map,struct,slice递归脱敏，没有敏感内容时changed为false，返回原值
+// 脱敏后map和struct转换为map[string]interface{}，slice转换为[]interface{}
+func (r *redactor) value(v reflect.Value, depth int) (interface{}, bool) {
+	if !v.IsValid() || depth > redactMaxDepth {
+		return nil, false
+	}
+
+	// 自定义编码的类型不脱敏，比如time.Time
+	if v.CanInterface() {
+		switch v.Interface().(type) {
+		case json.Marshaler, encoding.TextMarshaler:
+			return nil, false
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, false
+		}
+
+		return r.value(v.Elem(), depth+1)
+	case reflect.String:
+		if s := r.maskString(v.String()); s != v.String() {
+			return s, true
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() == reflect.String {
+			return r.mapValue(v, depth)
+		}
+	case reflect.Struct:
+		m := make(map[string]interface{}, v.NumField())
+		if r.structValue(v, depth, m) {
+			return m, true
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return r.sliceValue(v, depth)
+		}
+	}
+
+	return nil, false
+}
+
+// mapValue 对key为string的map脱敏
+func (r *redactor) mapValue(v reflect.Value, depth int) (interface{}, bool) {
+	m := make(map[string]interface{}, v.Len())
+	changed := false
+	iter := v.MapRange()
+	for iter.Next() {
+		key := iter.Key().String()
+		if r.sensitiveKey(key) {
+			m[key] = RedactMask
+			changed = true
+			continue
+		}
+
+		if val, ok := r.value(iter.Value(), depth+1); ok {
+			m[key] = val
+			changed = true
+			continue
+		}
+
+		m[key] = interfaceOf(iter.Value())
+	}
+
+	return m, changed
+}
+
+// structValue 对struct脱敏，字段名与json编码一致，嵌入的struct字段展开
+func (r *redactor) structValue(v reflect.Value, depth int, m map[string]interface{}) bool {
+	changed := false
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue // unexported
+		}
+
+		name, skip := jsonFieldName(sf)
+		if skip {
+			continue
+		}
+
+		fv := v.Field(i)
+		if sf.Anonymous && name == "" {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+
+				fv = fv.Elem()
+			}
+
+			if fv.Kind() == reflect.Struct {
+				changed = r.structValue(fv, depth+1, m) || changed
+				continue
+			}
+
+			if sf.PkgPath != "" {
+				continue
+			}
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+
+		if sf.Tag.Get("log") == redactTag || r.sensitiveKey(name) {
+			m[name] = RedactMask
+			changed = true
+			continue
+		}
+
+		if val, ok := r.value(fv, depth+1); ok {
+			m[name] = val
+			changed = true
+			continue
+		}
+
+		m[name] = interfaceOf(fv)
+	}
+
+	return changed
+}
+
+// sliceValue 对slice,array的元素脱敏
+func (r *redactor) sliceValue(v reflect.Value, depth int) (interface{}, bool) {
+	s := make([]interface{}, v.Len())
+	changed := false
+	for i := 0; i < v.Len(); i++ {
+		if val, ok := r.value(v.Index(i), depth+1); ok {
+			s[i] = val
+			changed = true
+			continue
+		}
+
+		s[i] = interfaceOf(v.Index(i))
+	}
+
+	return s, changed
+}
+
+// interfaceOf 返回v的值，通过未导出的嵌入字段访问的值返回nil
+func interfaceOf(v reflect.Value) interface{} {
+	if !v.CanInterface() {
+		return nil
+	}
+
+	return v.Interface()
+}
+
+// jsonFieldName 返回json tag中的字段名，json:"-"时skip为true
+func jsonFieldName(sf reflect.StructField) (name string, skip bool) {
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+
+	if i := strings.IndexByte(tag, ','); i >= 0 {
+		tag = tag[:i]
+	}
+
+	return tag, false
+}
+
+// maskCreditCard 通过Luhn校验的卡号只保留后4位
+func maskCreditCard(match string) string {
+	digits := make([]byte, 0, len(match))
+	for i := 0; i < len(match); i++ {
+		if match[i] >= '0' && match[i] <= '9' {
+			digits = append(digits, match[i])
+		}
+	}
+
+	if len(digits) < 13 || !luhnValid(digits) {
+		return match
+	}
+
+	return RedactMask + string(digits[len(digits)-4:])
+}
+
+// luhnValid Luhn校验
+func luhnValid(digits []byte) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+// maskEmail 邮箱只保留第一个字符和域名
+func maskEmail(match string) string {
+	at := strings.IndexByte(match, '@')
+	if at <= 0 {
+		return match
+	}
+
+	return match[:1] + "***" + match[at:]
+}
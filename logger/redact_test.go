@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type redactAccount struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+	Card     string `json:"card" log:"redact"`
+	Email    string
+}
+
+type redactUser struct {
+	redactAccount
+	ID       int                    `json:"id"`
+	Created  time.Time              `json:"created"`
+	Profile  *redactAccount         `json:"profile"`
+	Settings map[string]interface{} `json:"settings"`
+	Internal string                 `json:"-"`
+}
+
+// TestRedact test keys, struct tags and value masks in nested maps and structs.
+func TestRedact(t *testing.T) {
+	dir := t.TempDir()
+	l := New(WithLogDir(dir), WithLogFilename("redact.log"), WithWriteToFile(true), WithStdout(false),
+		WithRedactValues(DefaultValueMasks...))
+
+	user := redactUser{
+		redactAccount: redactAccount{Name: "joe", Password: "p@ss", Card: "4111", Email: "joe@example.com"},
+		ID:            1,
+		Created:       time.Date(2022, 8, 15, 0, 0, 0, 0, time.UTC),
+		Profile:       &redactAccount{Name: "alias", Card: "4222"},
+		Settings: map[string]interface{}{
+			"theme": "dark",
+			"oauth": map[string]interface{}{"access_token": "abc", "scopes": []interface{}{"read", "card 4111 1111 1111 1111"}},
+		},
+		Internal: "hidden",
+	}
+
+	ctx := WithoutRequestID(context.Background())
+	l.With("Authorization", "Bearer abc").Info(ctx, "login", map[string]interface{}{
+		"user":    user,
+		"request": map[string]interface{}{"headers": map[string]string{"Cookie": "sid=1"}, "retry": 3},
+	}, zap.String("contact", "mail to joe@example.com"), "db_password", "root", "plain", user.Profile,
+		"error", errors.New("send to joe@example.com failed"), "link", &url.URL{Scheme: "mailto", Opaque: "joe@example.com"},
+		zap.Strings("cc", []string{"ann@example.com", "ops"}))
+
+	line := readLogLines(t, filepath.Join(dir, "redact.log"))[0]
+	if line["Authorization"] != RedactMask || line["db_password"] != RedactMask ||
+		line["contact"] != "mail to j***@example.com" {
+		t.Fatalf("top level fields: %v", line)
+	}
+
+	u := line["user"].(map[string]interface{})
+	if u["name"] != "joe" || u["password"] != RedactMask || u["card"] != RedactMask || u["Email"] != "j***@example.com" ||
+		u["created"] != "2022-08-15T00:00:00Z" || u["Internal"] != nil {
+		t.Fatalf("user: %v", u)
+	}
+
+	if p := u["profile"].(map[string]interface{}); p["card"] != RedactMask || p["name"] != "alias" {
+		t.Fatalf("profile: %v", p)
+	}
+
+	oauth := u["settings"].(map[string]interface{})["oauth"].(map[string]interface{})
+	if oauth["access_token"] != RedactMask || oauth["scopes"].([]interface{})[1] != "card ******1111" {
+		t.Fatalf("oauth: %v", oauth)
+	}
+
+	request := line["request"].(map[string]interface{})
+	if request["headers"].(map[string]interface{})["Cookie"] != RedactMask || request["retry"] != float64(3) {
+		t.Fatalf("request: %v", request)
+	}
+
+	if plain := line["plain"].(map[string]interface{}); plain["card"] != RedactMask {
+		t.Fatalf("plain: %v", plain)
+	}
+
+	cc := line["cc"].([]interface{})
+	if line["error"] != "send to j***@example.com failed" || line["link"] != "mailto:j***@example.com" ||
+		cc[0] != "a***@example.com" || cc[1] != "ops" {
+		t.Fatalf("error,stringer and slice fields: %v %v %v", line["error"], line["link"], cc)
+	}
+}
+
+// TestRedactDefaultValues test values are not masked by default,
+// eg: a Luhn-valid 19-digit id and a nanosecond timestamp.
+func TestRedactDefaultValues(t *testing.T) {
+	dir := t.TempDir()
+	l := New(WithLogDir(dir), WithLogFilename("redact_default.log"), WithWriteToFile(true), WithStdout(false),
+		WithRedact(true))
+
+	id := "7451234567890123452"
+	ts := "1660521600000000000"
+	l.Info(WithoutRequestID(context.Background()), "order", "order_id", id, "ts", ts,
+		"email", "joe@example.com", "password", "p@ss")
+
+	line := readLogLines(t, filepath.Join(dir, "redact_default.log"))[0]
+	if line["order_id"] != id || line["ts"] != ts || line["email"] != "joe@example.com" ||
+		line["password"] != RedactMask {
+		t.Fatalf("log line: %v", line)
+	}
+}
+
+// TestRedactKeys test redaction is disabled by default and keys are matched by words.
+func TestRedactKeys(t *testing.T) {
+	dir := t.TempDir()
+	ctx := WithoutRequestID(context.Background())
+	fields := []interface{}{"password", "p@ss", "tokenizer_version", "v2", "userPassword", "p@ss",
+		"X-Api-Key", "k", "APIKey", "k", "access_token", "t", "passwordless", "yes"}
+
+	New(WithLogDir(dir), WithLogFilename("redact_keys.log"), WithWriteToFile(true), WithStdout(false)).
+		Info(ctx, "default", fields...)
+	New(WithLogDir(dir), WithLogFilename("redact_keys.log"), WithWriteToFile(true), WithStdout(false),
+		WithRedact(true)).Info(ctx, "redact", fields...)
+
+	lines := readLogLines(t, filepath.Join(dir, "redact_keys.log"))
+	if lines[0]["password"] != "p@ss" {
+		t.Fatalf("redacted by default: %v", lines[0])
+	}
+
+	want := map[string]interface{}{"password": RedactMask, "tokenizer_version": "v2", "userPassword": RedactMask,
+		"X-Api-Key": RedactMask, "APIKey": RedactMask, "access_token": RedactMask, "passwordless": "yes"}
+	for k, v := range want {
+		if lines[1][k] != v {
+			t.Fatalf("field %s: %v want: %v", k, lines[1][k], v)
+		}
+	}
+}
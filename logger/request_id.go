@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+)
+
+// noRequestIDKey ctx key to disable generating request id
+type noRequestIDKey struct{}
+
+// WithRequestID 返回携带request_id的ctx，如果id为空则生成一个新的request_id
+// 同一个ctx记录的多行日志将使用相同的request_id
+func WithRequestID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		id = NewRequestID()
+	}
+
+	return context.WithValue(ctx, XRequestID, id)
+}
+
+// EnsureRequestID 如果ctx中没有request_id，则生成一个并返回新的ctx，否则返回原ctx
+func EnsureRequestID(ctx context.Context) context.Context {
+	if ctx.Value(XRequestID) != nil {
+		return ctx
+	}
+
+	return WithRequestID(ctx, "")
+}
+
+// RequestID 返回ctx中的request_id，不存在返回空字符串
+func RequestID(ctx context.Context) string {
+	switch id := ctx.Value(XRequestID).(type) {
+	case string:
+		return id
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(id)
+	}
+}
+
+// WithoutRequestID 返回一个不自动生成request_id的ctx，适用于后台任务等非请求场景
+func WithoutRequestID(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRequestIDKey{}, true)
+}
+
+// needRequestID ctx中没有request_id时，是否需要自动生成
+func needRequestID(ctx context.Context) bool {
+	return ctx.Value(noRequestIDKey{}) == nil
+}
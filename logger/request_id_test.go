@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// readLogLines read json log lines of file
+func readLogLines(t *testing.T, filename string) []map[string]interface{} {
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := make(map[string]interface{})
+		if err = json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatal(err)
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// TestEnsureRequestID test the same request id is used by the log lines of a context.
+func TestEnsureRequestID(t *testing.T) {
+	dir := t.TempDir()
+	l := New(WithLogDir(dir), WithLogFilename("req.log"), WithWriteToFile(true), WithStdout(false))
+
+	ctx := EnsureRequestID(context.Background())
+	if EnsureRequestID(ctx) != ctx || RequestID(ctx) == "" {
+		t.Fatal("request id is not assigned once")
+	}
+
+	l.Info(ctx, "first")
+	l.Info(ctx, "second")
+	l.Info(context.Background(), "no request id")
+
+	auto := New(WithLogDir(dir), WithLogFilename("req.log"), WithWriteToFile(true), WithStdout(false),
+		WithAutoRequestID(true))
+	auto.Info(context.Background(), "auto")
+	auto.Info(WithoutRequestID(context.Background()), "background job")
+
+	lines := readLogLines(t, filepath.Join(dir, "req.log"))
+	if len(lines) != 5 {
+		t.Fatalf("log lines: %d", len(lines))
+	}
+
+	key := XRequestID.String()
+	if lines[0][key] != RequestID(ctx) || lines[1][key] != RequestID(ctx) {
+		t.Fatalf("request id: %v %v", lines[0][key], lines[1][key])
+	}
+
+	if _, ok := lines[2][key]; ok {
+		t.Fatalf("request id is generated by default: %v", lines[2][key])
+	}
+
+	if id, _ := lines[3][key].(string); id == "" {
+		t.Fatal("auto request id is not generated")
+	}
+
+	if _, ok := lines[4][key]; ok {
+		t.Fatalf("background job request id: %v", lines[4][key])
+	}
+}
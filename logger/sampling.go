@@ -0,0 +1,231 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// dropReasonSampling 被采样丢弃
+	dropReasonSampling = "sampling"
+
+	// dropReasonRateLimit 被限流丢弃
+	dropReasonRateLimit = "rate_limit"
+)
+
+// defaultDropSummaryInterval 默认丢弃日志统计的输出间隔
+const defaultDropSummaryInterval = time.Minute
+
+// samplingOption 采样配置，每个tick内相同级别和内容的日志，前first条记录，之后每thereafter条记录一条
+type samplingOption struct {
+	tick       time.Duration
+	first      int
+	thereafter int
+}
+
+// rateLimitOption 限流配置，每个interval内相同内容的日志最多记录limit条
+type rateLimitOption struct {
+	limit    int
+	interval time.Duration
+}
+
+// wrapDropCore 根据采样和限流配置包装core
+func (z *zapLogWriter) wrapDropCore(core zapcore.Core) zapcore.Core {
+	if z.sampling == nil && z.rateLimit == nil {
+		return core
+	}
+
+	stats := &dropStats{
+		base:     core,
+		interval: z.dropSummaryInterval,
+		last:     time.Now().UnixNano(),
+		done:     make(chan struct{}),
+	}
+	z.dropStats = stats
+	inner := core
+	if z.rateLimit != nil {
+		inner = &rateLimitCore{
+			Core:    inner,
+			limiter: &rateLimiter{limit: int64(z.rateLimit.limit), interval: z.rateLimit.interval},
+			stats:   stats,
+		}
+	}
+
+	if z.sampling != nil {
+		inner = zapcore.NewSamplerWithOptions(inner, z.sampling.tick, z.sampling.first, z.sampling.thereafter,
+			zapcore.SamplerHook(func(ent zapcore.Entry, dec zapcore.SamplingDecision) {
+				if dec&zapcore.LogDropped > 0 {
+					stats.add(dropReasonSampling, ent.Level)
+				}
+			}))
+	}
+
+	return &dropCore{Core: inner, direct: core, stats: stats}
+}
+
+// dropStats 丢弃日志统计，定期输出一条汇总日志
+type dropStats struct {
+	sampled     int64 // 上次汇总后被采样丢弃的行数
+	rateLimited int64 // 上次汇总后被限流丢弃的行数
+	last        int64 // 上次汇总时间，unix nano
+
+	interval time.Duration // 汇总间隔，<=0不输出汇总日志
+	base     zapcore.Core  // 汇总日志由底层core按sink的级别写入，不被采样和限流
+
+	stopOnce sync.Once
+	done     chan struct{} // 关闭后停止定时汇总
+}
+
+// start 启动定时汇总goroutine，没有日志写入时也按interval输出汇总，stop时退出
+func (s *dropStats) start() {
+	if s.interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				s.summary(now, true)
+			case <-s.done:
+				return
+			}
+		}
+	}()
+}
+
+// stop 停止定时汇总goroutine，并输出剩余的丢弃日志汇总
+func (s *dropStats) stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+		s.summary(time.Now(), true)
+	})
+}
+
+// add 记录丢弃的日志
+func (s *dropStats) add(reason string, level zapcore.Level) {
+	if reason == dropReasonSampling {
+		atomic.AddInt64(&s.sampled, 1)
+	} else {
+		atomic.AddInt64(&s.rateLimited, 1)
+	}
+
+	logDropped.With(reason, level.String()).Inc()
+}
+
+// summary 距上次汇总超过interval或force为true时，输出丢弃日志汇总，由定时goroutine和Sync调用
+func (s *dropStats) summary(now time.Time, force bool) {
+	if s.interval <= 0 {
+		return
+	}
+
+	last := atomic.LoadInt64(&s.last)
+	if !force && now.UnixNano()-last < int64(s.interval) {
+		return
+	}
+
+	if !atomic.CompareAndSwapInt64(&s.last, last, now.UnixNano()) {
+		return
+	}
+
+	sampled := atomic.SwapInt64(&s.sampled, 0)
+	rateLimited := atomic.SwapInt64(&s.rateLimited, 0)
+	if sampled == 0 && rateLimited == 0 {
+		return
+	}
+
+	// 通过Check写入，只有级别允许warn的sink才输出汇总
+	ent := zapcore.Entry{Level: zapcore.WarnLevel, Time: now, Message: "log lines dropped"}
+	if ce := s.base.Check(ent, nil); ce != nil {
+		ce.Write(
+			zap.Int64(dropReasonSampling, sampled),
+			zap.Int64(dropReasonRateLimit, rateLimited),
+			zap.Duration("since", time.Duration(now.UnixNano()-last)),
+		)
+	}
+}
+
+// dropCore 采样和限流的core，Error及以上级别的日志不会被丢弃，Sync时输出丢弃日志汇总
+type dropCore struct {
+	zapcore.Core
+	direct zapcore.Core // 不被采样和限流的core
+	stats  *dropStats
+}
+
+// With implements zapcore.Core
+func (c *dropCore) With(fields []zapcore.Field) zapcore.Core {
+	return &dropCore{Core: c.Core.With(fields), direct: c.direct.With(fields), stats: c.stats}
+}
+
+// Check implements zapcore.Core
+func (c *dropCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level >= zapcore.ErrorLevel {
+		return c.direct.Check(ent, ce)
+	}
+
+	return c.Core.Check(ent, ce)
+}
+
+// Sync implements zapcore.Core
+func (c *dropCore) Sync() error {
+	c.stats.summary(time.Now(), true)
+	return c.Core.Sync()
+}
+
+// rateLimiter 按日志内容计数的固定窗口限流器
+type rateLimiter struct {
+	limit    int64
+	interval time.Duration
+
+	mu     sync.Mutex
+	window int64            // 当前窗口序号
+	counts map[string]int64 // 当前窗口每条日志内容的行数
+}
+
+// allow 当前窗口内msg的行数未超过limit返回true
+func (r *rateLimiter) allow(msg string, now time.Time) bool {
+	window := now.UnixNano() / int64(r.interval)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if window != r.window || r.counts == nil {
+		r.window = window
+		r.counts = make(map[string]int64)
+	}
+
+	r.counts[msg]++
+	return r.counts[msg] <= r.limit
+}
+
+// rateLimitCore 按日志内容限流的core
+type rateLimitCore struct {
+	zapcore.Core
+	limiter *rateLimiter
+	stats   *dropStats
+}
+
+// With implements zapcore.Core
+func (c *rateLimitCore) With(fields []zapcore.Field) zapcore.Core {
+	return &rateLimitCore{Core: c.Core.With(fields), limiter: c.limiter, stats: c.stats}
+}
+
+// Check implements zapcore.Core
+func (c *rateLimitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+
+	if !c.limiter.allow(ent.Message, ent.Time) {
+		c.stats.add(dropReasonRateLimit, ent.Level)
+		return ce
+	}
+
+	return c.Core.Check(ent, ce)
+}
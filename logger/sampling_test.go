@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestSamplingAndRateLimit test dropped lines are counted and summarized.
+func TestSamplingAndRateLimit(t *testing.T) {
+	dir := t.TempDir()
+	l := New(WithLogDir(dir), WithLogFilename("drop.log"), WithWriteToFile(true), WithStdout(false),
+		WithSampling(time.Minute, 2, 0), WithRateLimit(3, time.Minute), WithDropSummary(time.Hour))
+
+	ctx := WithoutRequestID(context.Background())
+	for i := 0; i < 5; i++ {
+		l.Info(ctx, "sampled")
+	}
+
+	// the sampler counts by level and message,so each line of different level passes it
+	l.Info(ctx, "limited")
+	l.Warn(ctx, "limited")
+	l.Info(ctx, "limited")
+	before := logDropped.With(dropReasonRateLimit, "warn").Value()
+	l.Warn(ctx, "limited")
+	if after := logDropped.With(dropReasonRateLimit, "warn").Value(); after != before+1 {
+		t.Fatalf("rate limit dropped metric: %v -> %v", before, after)
+	}
+
+	// error and above are never sampled or rate limited
+	for i := 0; i < 5; i++ {
+		l.Error(ctx, "limited")
+	}
+	l.DPanic(ctx, "limited")
+
+	if err := l.(*zapLogWriter).fLogger.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	counts := make(map[string]int)
+	var summary map[string]interface{}
+	for _, line := range readLogLines(t, filepath.Join(dir, "drop.log")) {
+		counts[line["msg"].(string)]++
+		if line["msg"] == "log lines dropped" {
+			summary = line
+		}
+	}
+
+	if counts["sampled"] != 2 || counts["limited"] != 9 || summary == nil {
+		t.Fatalf("log lines: %v", counts)
+	}
+
+	if summary[dropReasonSampling] != float64(3) || summary[dropReasonRateLimit] != float64(1) {
+		t.Fatalf("drop summary: %v", summary)
+	}
+}
+
+// TestDropSummaryTicker test the drop summary is written without new log lines and stops on Close.
+func TestDropSummaryTicker(t *testing.T) {
+	dir := t.TempDir()
+	l := New(WithLogDir(dir), WithLogFilename("ticker.log"), WithWriteToFile(true), WithStdout(false),
+		WithSampling(time.Minute, 1, 0), WithDropSummary(20*time.Millisecond))
+	defer l.(*zapLogWriter).Close()
+
+	ctx := WithoutRequestID(context.Background())
+	l.Info(ctx, "sampled")
+	l.Info(ctx, "sampled")
+
+	filename := filepath.Join(dir, "ticker.log")
+	for i := 0; i < 100; i++ {
+		for _, line := range readLogLines(t, filename) {
+			if line["msg"] == "log lines dropped" && line[dropReasonSampling] == float64(1) {
+				return
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("drop summary is not written by ticker")
+}
+
+// TestDropSummarySinkLevel test the drop summary is only written to the sinks which enable warn level.
+func TestDropSummarySinkLevel(t *testing.T) {
+	dir := t.TempDir()
+	l := New(WithLogDir(dir), WithSampling(time.Minute, 1, 0), WithSinks(
+		Sink{Type: SinkFile, Filename: "app.log"},
+		Sink{Type: SinkFile, Filename: "error.log", Level: "error"},
+	))
+
+	ctx := WithoutRequestID(context.Background())
+	l.Info(ctx, "sampled")
+	l.Info(ctx, "sampled")
+	if err := l.(*zapLogWriter).Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := readLogLines(t, filepath.Join(dir, "app.log"))
+	if len(lines) != 2 || lines[1]["msg"] != "log lines dropped" {
+		t.Fatalf("app.log lines: %v", lines)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "error.log")); !os.IsNotExist(err) {
+		t.Fatalf("drop summary is written to error.log: %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// SinkFile 输出到日志文件，按logger的max_size,max_age,compress切割
+	SinkFile = "file"
+
+	// SinkStdout 输出到终端stdout
+	SinkStdout = "stdout"
+
+	// SinkStderr 输出到终端stderr
+	SinkStderr = "stderr"
+)
+
+// 终端不支持fsync，这里忽略stdout,stderr的Sync
+var (
+	stdoutWriter = zapcore.AddSync(struct{ io.Writer }{os.Stdout})
+	stderrWriter = zapcore.AddSync(struct{ io.Writer }{os.Stderr})
+)
+
+// Sink 日志输出配置，每个sink可以设置不同的级别和格式
+/**
+logger:
+  sinks:
+    - type: file
+      filename: app.log
+      level: debug
+      format: json
+    - type: stdout
+      level: info
+      format: console
+      color: true
+    - type: file
+      filename: error.log
+      level: error
+*/
+type Sink struct {
+	Type     string `mapstructure:"type"`     // file,stdout,stderr
+	Filename string `mapstructure:"filename"` // type为file时的文件名，相对路径的文件放在logDir中
+	Level    string `mapstructure:"level"`    // 最低级别，为空时只使用logger的级别
+	Format   string `mapstructure:"format"`   // json或console，为空时使用logger的格式
+	Color    bool   `mapstructure:"color"`    // 日志级别是否染色
+}
+
+// initSinks 每个sink创建一个core
+func (z *zapLogWriter) initSinks() (zapcore.Core, error) {
+	cores := make([]zapcore.Core, 0, len(z.sinks))
+	for _, sink := range z.sinks {
+		core, err := z.newSinkCore(sink)
+		if err != nil {
+			return nil, err
+		}
+
+		cores = append(cores, core)
+	}
+
+	return zapcore.NewTee(cores...), nil
+}
+
+// newSinkCore 根据sink配置创建core
+func (z *zapLogWriter) newSinkCore(sink Sink) (zapcore.Core, error) {
+	var enabler zapcore.LevelEnabler = allLevels
+	if sink.Level != "" {
+		level, err := parseLevel(sink.Level)
+		if err != nil {
+			return nil, fmt.Errorf("sink %s level error: %w", sink.Type, err)
+		}
+
+		enabler = level
+	}
+
+	jsonFormat := z.jsonFormat
+	switch sink.Format {
+	case "":
+	case "json":
+		jsonFormat = true
+	case "console":
+		jsonFormat = false
+	default:
+		return nil, fmt.Errorf("sink %s format %q is not supported", sink.Type, sink.Format)
+	}
+
+	var ws zapcore.WriteSyncer
+	switch sink.Type {
+	case SinkFile:
+		filename := sink.Filename
+		if filename == "" {
+			filename = z.logFilename
+		}
+
+		if filename == "" {
+			filename = filepath.Base(os.Args[0])
+		}
+
+		filename, err := z.filePath(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		ws = z.newFileWriter(filename)
+	case SinkStdout:
+		ws = stdoutWriter
+	case SinkStderr:
+		ws = stderrWriter
+	default:
+		return nil, fmt.Errorf("sink type %q is not supported", sink.Type)
+	}
+
+	return zapcore.NewCore(z.newEncoder(jsonFormat, sink.Color), z.wrapAsync(ws), enabler), nil
+}
@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// TestSinks test per-sink level and format.
+func TestSinks(t *testing.T) {
+	dir := t.TempDir()
+	l := New(WithLogDir(dir), WithLogLevel(zapcore.DebugLevel), WithSinks(
+		Sink{Type: SinkFile, Filename: "app.log", Format: "json"},
+		Sink{Type: SinkFile, Filename: "app.txt", Level: "info", Format: "console"},
+		Sink{Type: SinkFile, Filename: "error.log", Level: "error"},
+	))
+
+	ctx := WithoutRequestID(context.Background())
+	l.Debug(ctx, "debug line")
+	l.Info(ctx, "info line")
+	l.Error(ctx, "error line")
+	if err := l.(*zapLogWriter).Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if lines := readLogLines(t, filepath.Join(dir, "app.log")); len(lines) != 3 {
+		t.Fatalf("app.log lines: %d", len(lines))
+	}
+
+	if lines := readLogLines(t, filepath.Join(dir, "error.log")); len(lines) != 1 || lines[0]["msg"] != "error line" {
+		t.Fatalf("error.log lines: %v", lines)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "app.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if text := string(b); strings.Count(text, "\n") != 2 || !strings.Contains(text, "\tinfo\tinfo line\t") {
+		t.Fatalf("app.txt: %s", text)
+	}
+}
@@ -0,0 +1,199 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// slogHandler slog.Handler，日志记录到Logger中，ctx中的字段同样会被记录
+type slogHandler struct {
+	l    Logger
+	goas []groupOrAttrs // WithGroup和WithAttrs按调用顺序保存
+}
+
+// groupOrAttrs group名称或属性
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+// NewSlogHandler 创建日志记录到l的slog.Handler
+// slog的group记录为嵌套的字段，比如 {"http":{"method":"GET"}}
+func NewSlogHandler(l Logger) slog.Handler {
+	return &slogHandler{l: l}
+}
+
+// SetSlogDefault 将l设置为slog的默认handler，slog和标准库log的日志都会记录到l中
+func SetSlogDefault(l Logger) {
+	slog.SetDefault(slog.New(NewSlogHandler(l)))
+}
+
+// levelEnabler 判断日志级别是否开启
+type levelEnabler interface {
+	enabled(level zapcore.Level) bool
+}
+
+// enabled 全局级别或任一模块级别开启时返回true
+func (z *zapLogWriter) enabled(level zapcore.Level) bool {
+	return z.fLogger.Core().Enabled(level)
+}
+
+// recordWriter 按slog.Record的时间和调用位置写日志的logger
+type recordWriter interface {
+	writeRecord(ctx context.Context, level zapcore.Level, t time.Time, pc uintptr, msg string, fields ...interface{})
+}
+
+// writeRecord 使用slog记录的时间，开启caller时记录slog调用位置，而不是slog handler内部的位置
+func (z *zapLogWriter) writeRecord(ctx context.Context, level zapcore.Level, t time.Time, pc uintptr,
+	msg string, fields ...interface{}) {
+	ce := z.fLogger.WithOptions(zap.WithCaller(false)).Check(level, msg)
+	if ce == nil {
+		return
+	}
+
+	if !t.IsZero() {
+		ce.Time = t
+	}
+
+	if z.addCaller && z.callerSkip > 0 && pc != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		ce.Caller = zapcore.NewEntryCaller(frame.PC, frame.File, frame.Line, frame.PC != 0)
+	}
+
+	ce.Write(z.parseFields(ctx, fields)...)
+}
+
+// Enabled implements slog.Handler
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	if e, ok := h.l.(levelEnabler); ok {
+		return e.enabled(zapLevel(level))
+	}
+
+	return true
+}
+
+// Handle implements slog.Handler
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	goas := h.goas
+	if r.NumAttrs() == 0 {
+		// 没有属性的group不记录
+		for len(goas) > 0 && goas[len(goas)-1].group != "" {
+			goas = goas[:len(goas)-1]
+		}
+	}
+
+	fields := make(map[string]interface{}, r.NumAttrs()+len(goas))
+	cur := fields
+	for _, goa := range goas {
+		if goa.group != "" {
+			group := make(map[string]interface{})
+			cur[goa.group] = group
+			cur = group
+			continue
+		}
+
+		for _, a := range goa.attrs {
+			addSlogAttr(cur, a)
+		}
+	}
+
+	r.Attrs(func(a slog.Attr) bool {
+		addSlogAttr(cur, a)
+		return true
+	})
+
+	level := zapLevel(r.Level)
+	if w, ok := h.l.(recordWriter); ok {
+		w.writeRecord(ctx, level, r.Time, r.PC, r.Message, fields)
+		return nil
+	}
+
+	switch level {
+	case zapcore.DebugLevel:
+		h.l.Debug(ctx, r.Message, fields)
+	case zapcore.InfoLevel:
+		h.l.Info(ctx, r.Message, fields)
+	case zapcore.WarnLevel:
+		h.l.Warn(ctx, r.Message, fields)
+	default:
+		h.l.Error(ctx, r.Message, fields)
+	}
+
+	return nil
+}
+
+// WithAttrs implements slog.Handler
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	return h.with(groupOrAttrs{attrs: attrs})
+}
+
+// WithGroup implements slog.Handler
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return h.with(groupOrAttrs{group: name})
+}
+
+// with 返回新的handler，goas复制一份，防止多个handler共用底层数组
+func (h *slogHandler) with(goa groupOrAttrs) *slogHandler {
+	goas := make([]groupOrAttrs, 0, len(h.goas)+1)
+	goas = append(goas, h.goas...)
+	return &slogHandler{l: h.l, goas: append(goas, goa)}
+}
+
+// addSlogAttr 将slog属性添加到m中，group属性记录为嵌套的map，key为空的group展开
+func addSlogAttr(m map[string]interface{}, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() != slog.KindGroup {
+		m[a.Key] = a.Value.Any()
+		return
+	}
+
+	attrs := a.Value.Group()
+	if len(attrs) == 0 {
+		return
+	}
+
+	group := m
+	if a.Key != "" {
+		group = make(map[string]interface{}, len(attrs))
+		m[a.Key] = group
+	}
+
+	for _, ga := range attrs {
+		addSlogAttr(group, ga)
+	}
+}
+
+// zapLevel slog级别转换为zap级别，高于error的级别记录为error
+func zapLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return zapcore.DebugLevel
+	case level < slog.LevelWarn:
+		return zapcore.InfoLevel
+	case level < slog.LevelError:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// TestSlogHandler test slog records keep groups, attrs and ctx fields.
+func TestSlogHandler(t *testing.T) {
+	dir := t.TempDir()
+	l := New(WithLogDir(dir), WithLogFilename("slog.log"), WithWriteToFile(true), WithStdout(false),
+		WithLogLevel(zapcore.InfoLevel), WithRedact(true))
+	sl := slog.New(NewSlogHandler(l))
+
+	ctx := context.WithValue(context.Background(), XRequestID, "req-1")
+	if sl.Enabled(ctx, slog.LevelDebug) {
+		t.Fatal("debug level is enabled")
+	}
+
+	sl.DebugContext(ctx, "debug line")
+	sl.With("service", "order").WithGroup("http").With("method", "GET").
+		InfoContext(ctx, "request", "status", 200, slog.Group("user", "id", 1, "password", "secret"))
+	sl.WithGroup("empty").Warn("no attrs")
+
+	lines := readLogLines(t, filepath.Join(dir, "slog.log"))
+	if len(lines) != 2 {
+		t.Fatalf("log lines: %v", lines)
+	}
+
+	line := lines[0]
+	http := line["http"].(map[string]interface{})
+	user := http["user"].(map[string]interface{})
+	if line["service"] != "order" || line[XRequestID.String()] != "req-1" || http["method"] != "GET" ||
+		http["status"] != float64(200) || user["id"] != float64(1) || user["password"] != RedactMask {
+		t.Fatalf("slog line: %v", line)
+	}
+
+	if _, ok := lines[1]["empty"]; ok || lines[1]["level"] != "warn" {
+		t.Fatalf("slog line without attrs: %v", lines[1])
+	}
+}
+
+// TestSlogHandlerRecord test the record time and the caller of slog are kept.
+func TestSlogHandlerRecord(t *testing.T) {
+	dir := t.TempDir()
+	l := New(WithLogDir(dir), WithLogFilename("slog_record.log"), WithWriteToFile(true), WithStdout(false),
+		WithAddCaller(true), WithCallerSkip(1))
+	h := NewSlogHandler(l)
+
+	recordTime := time.Date(2022, 8, 15, 10, 30, 0, 0, time.UTC)
+	pc, file, line, _ := runtime.Caller(0)
+	if err := h.Handle(context.Background(), slog.NewRecord(recordTime, slog.LevelInfo, "record", pc)); err != nil {
+		t.Fatal(err)
+	}
+
+	slog.New(h).Info("caller")
+	_, _, callerLine, _ := runtime.Caller(0)
+
+	lines := readLogLines(t, filepath.Join(dir, "slog_record.log"))
+	if len(lines) != 2 {
+		t.Fatalf("log lines: %v", lines)
+	}
+
+	ts, err := time.Parse("2006-01-02T15:04:05.000Z0700", lines[0]["time_local"].(string))
+	if err != nil || !ts.Equal(recordTime) {
+		t.Fatalf("record time: %v error: %v", lines[0]["time_local"], err)
+	}
+
+	if caller := lines[0]["caller_line"]; caller != file+":"+strconv.Itoa(line) {
+		t.Fatalf("record caller: %v", caller)
+	}
+
+	if caller := lines[1]["caller_line"].(string); !strings.HasSuffix(caller, "slog_test.go:"+strconv.Itoa(callerLine-1)) {
+		t.Fatalf("slog caller: %v", caller)
+	}
+}
@@ -0,0 +1,211 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// timeWriter 按时间切割的日志文件writer，文件名由pattern和当前时间生成
+// 比如 app-%Y%m%d%H.log 每小时生成一个 app-2022081510.log 文件
+// 创建后pattern,interval,maxAge,maxBackups不再修改，删除旧文件的goroutine可以直接读取
+type timeWriter struct {
+	pattern    string        // 日志文件路径模式
+	interval   time.Duration // 切割间隔，最大24小时
+	maxAge     int           // 日志保留天数，<=0不删除
+	maxBackups int           // 保留的旧日志文件数，<=0不限制
+
+	mu       sync.Mutex
+	file     *os.File
+	filename string    // 当前日志文件
+	next     time.Time // 下一次切割时间
+	now      func() time.Time
+
+	cleanup   sync.WaitGroup // 正在删除旧日志文件的goroutine，Close时等待
+	cleanupMu sync.Mutex     // 删除旧日志文件的goroutine依次执行
+}
+
+// newTimeWriter 创建timeWriter
+func newTimeWriter(pattern string, interval time.Duration, maxAge, maxBackups int) *timeWriter {
+	if interval <= 0 || interval > 24*time.Hour {
+		interval = 24 * time.Hour
+	}
+
+	return &timeWriter{
+		pattern:    pattern,
+		interval:   interval,
+		maxAge:     maxAge,
+		maxBackups: maxBackups,
+		now:        time.Now,
+	}
+}
+
+// defaultFilenamePattern 根据切割间隔在文件名后缀前添加时间占位符
+func defaultFilenamePattern(filename string, interval time.Duration) string {
+	layout := "%Y%m%d"
+	if interval < time.Hour {
+		layout = "%Y%m%d%H%M"
+	} else if interval < 24*time.Hour {
+		layout = "%Y%m%d%H"
+	}
+
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + "-" + layout + ext
+}
+
+// Write implements io.Writer，到达切割时间时打开新文件
+func (w *timeWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil || !w.now().Before(w.next) {
+		if err := w.openNew(); err != nil {
+			return 0, err
+		}
+	}
+
+	return w.file.Write(p)
+}
+
+// Rotate 关闭并重新打开当前时间的日志文件，适用于外部logrotate移动文件后
+func (w *timeWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.openNew()
+}
+
+// Close implements io.Closer，等待删除旧日志文件完成
+func (w *timeWriter) Close() error {
+	w.mu.Lock()
+	err := w.close()
+	w.mu.Unlock()
+
+	w.cleanup.Wait()
+	return err
+}
+
+// Sync 将日志文件写入磁盘
+func (w *timeWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+
+	return w.file.Sync()
+}
+
+// close 关闭当前文件
+func (w *timeWriter) close() error {
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// openNew 打开当前时间段的日志文件，文件已存在时追加写入
+func (w *timeWriter) openNew() error {
+	if err := w.close(); err != nil {
+		return err
+	}
+
+	now := w.now()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start := dayStart.Add(now.Sub(dayStart).Truncate(w.interval))
+	w.next = start.Add(w.interval)
+	if dayEnd := dayStart.AddDate(0, 0, 1); w.next.After(dayEnd) {
+		w.next = dayEnd
+	}
+
+	filename := formatPattern(w.pattern, start)
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	rotated := w.filename != "" && w.filename != filename
+	w.file = f
+	w.filename = filename
+	if rotated && (w.maxAge > 0 || w.maxBackups > 0) {
+		w.cleanup.Add(1)
+		go func() {
+			defer w.cleanup.Done()
+			w.removeOld(now)
+		}()
+	}
+
+	return nil
+}
+
+// removeOld 删除超过maxBackups或maxAge的旧日志文件，now为切割时间
+func (w *timeWriter) removeOld(now time.Time) {
+	w.cleanupMu.Lock()
+	defer w.cleanupMu.Unlock()
+
+	// 执行时可能已经再次切割，读取最新的当前文件，避免删除正在写入的文件
+	w.mu.Lock()
+	current := w.filename
+	w.mu.Unlock()
+
+	matches, err := filepath.Glob(w.globPattern())
+	if err != nil {
+		return
+	}
+
+	type backup struct {
+		name    string
+		modTime time.Time
+	}
+
+	backups := make([]backup, 0, len(matches))
+	for _, name := range matches {
+		if name == current {
+			continue
+		}
+
+		if info, err := os.Stat(name); err == nil && !info.IsDir() {
+			backups = append(backups, backup{name: name, modTime: info.ModTime()})
+		}
+	}
+
+	// 最新的文件在前，修改时间相同时按文件名倒序
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].modTime.Equal(backups[j].modTime) {
+			return backups[i].name > backups[j].name
+		}
+
+		return backups[i].modTime.After(backups[j].modTime)
+	})
+
+	cutoff := now.AddDate(0, 0, -w.maxAge)
+	for i, b := range backups {
+		if (w.maxBackups > 0 && i >= w.maxBackups) || (w.maxAge > 0 && b.modTime.Before(cutoff)) {
+			_ = os.Remove(b.name)
+		}
+	}
+}
+
+// globPattern 将时间占位符替换为*，用于查找旧日志文件
+func (w *timeWriter) globPattern() string {
+	return strings.NewReplacer("%Y", "*", "%m", "*", "%d", "*", "%H", "*", "%M", "*").Replace(w.pattern)
+}
+
+// formatPattern 替换文件名模式中的时间占位符，支持%Y,%m,%d,%H,%M
+func formatPattern(pattern string, t time.Time) string {
+	return strings.NewReplacer(
+		"%Y", t.Format("2006"), "%m", t.Format("01"), "%d", t.Format("02"),
+		"%H", t.Format("15"), "%M", t.Format("04"),
+	).Replace(pattern)
+}
@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestTimeWriter test hourly rotation, retention by count and Rotate.
+func TestTimeWriter(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2022, 8, 15, 10, 30, 0, 0, time.Local)
+	w := newTimeWriter(filepath.Join(dir, defaultFilenamePattern("app.log", time.Hour)), time.Hour, 0, 1)
+	w.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if _, err := w.Write([]byte("line\n")); err != nil {
+			t.Fatal(err)
+		}
+
+		now = now.Add(time.Hour)
+	}
+
+	if err := os.Remove(w.filename); err != nil {
+		t.Fatal(err)
+	}
+
+	// external logrotate moved the file,Rotate reopens it
+	if err := w.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = w.Write([]byte("after rotate\n"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "app-2022081513.log")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Close waits for removing of old files
+	matches, _ := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	if len(matches) != 2 {
+		t.Fatalf("log files: %v", matches)
+	}
+}
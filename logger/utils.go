@@ -3,28 +3,56 @@ package logger
 import (
 	"crypto/md5"
 	"encoding/hex"
-	"fmt"
 	"math/rand"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/go-god/msa/idgen"
 )
 
+// rnd local random source,the global math/rand source is not seeded by this package
+var rnd = struct {
+	sync.Mutex
+	*rand.Rand
+}{Rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+// idGenerator request id generator,default uuid v4
+var idGenerator atomic.Value
+
+// idGeneratorHolder atomic.Value needs the same concrete type for all stored values
+type idGeneratorHolder struct {
+	g idgen.IDGenerator
+}
+
 func init() {
-	rand.Seed(time.Now().UnixNano())
+	idGenerator.Store(idGeneratorHolder{g: idgen.UUIDv4})
+}
+
+// SetIDGenerator 设置生成request_id的generator，默认为idgen.UUIDv4，并发安全
+func SetIDGenerator(g idgen.IDGenerator) {
+	if g != nil {
+		idGenerator.Store(idGeneratorHolder{g: g})
+	}
+}
+
+// NewRequestID 使用SetIDGenerator设置的generator生成request_id
+func NewRequestID() string {
+	return idGenerator.Load().(idGeneratorHolder).g.NewID()
 }
 
-// RndUUID realizes unique uuid based on time ns and random number
-// There is no duplication of uuid on a single machine
-// If you want to generate non-duplicate uuid on a distributed architecture
-// Just add some custom strings in front of rndStr
-// Return format: eba1e8cd-0460-4910-49c6-44bdf3cf024d
+// RndUUID return a random RFC 4122 uuid version 4
+// Return format: 0f8fad5b-d9cb-469f-a165-70867728950e
 func RndUUID() string {
-	s := RndUUIDMd5()
-	return fmt.Sprintf("%s-%s-%s-%s-%s", s[:8], s[8:12], s[12:16], s[16:20], s[20:])
+	return idgen.NewUUIDv4()
 }
 
 // RndUUIDMd5 make an md5 uuid
+//
+// Deprecated: it is based on time ns and a small random number and may collide,
+// use RndUUID or the generators of idgen package instead.
 func RndUUIDMd5() string {
 	ns := time.Now().UnixNano()
 	rndStr := strings.Join([]string{
@@ -40,7 +68,9 @@ func RandInt64(min, max int64) int64 {
 		return max
 	}
 
-	return rand.Int63n(max-min) + min
+	rnd.Lock()
+	defer rnd.Unlock()
+	return rnd.Int63n(max-min) + min
 }
 
 // Md5 md5 func
@@ -5,12 +5,15 @@ package logger
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"runtime/debug"
+	"strings"
 	"time"
 
+	"github.com/go-god/msa/idgen"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -24,20 +27,26 @@ const tmFmtWithMS = "2006-01-02 15:04:05.999"
 
 // zapLogWriter zap log entry data.
 type zapLogWriter struct {
-	maxAge    int  // 日志保留天数
-	maxSize   int  // 日志大小，单位为MB
-	compress  bool // 是否日志压缩，默认日志不压缩
-	addCaller bool // 是否添加行号，默认不添加文件行号
+	maxAge     int           // 日志保留天数
+	maxSize    int           // 日志大小，单位为MB
+	maxBackups int           // 保留的旧日志文件数，默认不限制
+	rotateTime time.Duration // 按时间切割的间隔，文件名包含%Y等时间占位符时默认按天切割
+	compress   bool          // 是否日志压缩，默认日志不压缩
+	addCaller  bool          // 是否添加行号，默认不添加文件行号
 
 	// addCaller = true,并且 callerSkip > 0 会设置zap.AddCallerSkip
 	callerSkip int
 
-	logLevel       zapcore.Level // zap日志级别
-	logWriteToFile bool          // 日志是否写入文件中
-	logFilename    string        // 日志文件名，不包含路径，比如go-zap.log
-	logDir         string        // 日志存放的目录
-	jsonFormat     bool          // 是否json格式化
-	stdout         bool          // 是否输出到终端
+	logLevel       zapcore.Level  // zap日志级别
+	levels         *levelRegistry // 运行时可修改的全局日志级别和模块日志级别
+	logWriteToFile bool           // 日志是否写入文件中
+	logFilename    string         // 日志文件名，不包含路径，比如go-zap.log
+	logDir         string         // 日志存放的目录
+	jsonFormat     bool           // 是否json格式化
+	stdout         bool           // 是否输出到终端
+
+	// logger name，作为模块名用于模块日志级别
+	name string
 
 	// 日志是否染色
 	// For example, InfoLevel is serialized to "info" and colored blue.
@@ -46,6 +55,39 @@ type zapLogWriter struct {
 	// hostname host
 	hostname string
 
+	// ctx中没有request_id时是否每行日志自动生成，默认为false
+	autoRequestID bool
+
+	// 自动生成request_id的generator，为空时使用 SetIDGenerator 设置的generator
+	idGenerator idgen.IDGenerator
+
+	// 敏感字段脱敏，默认不开启，开启后按字段名脱敏，按值脱敏需要设置redactMasks
+	redact      bool
+	redactKeys  []string
+	redactMasks []ValueMask
+	redactor    *redactor
+
+	// 日志采样和按内容限流，为空时不开启
+	sampling  *samplingOption
+	rateLimit *rateLimitOption
+
+	// 丢弃日志汇总的输出间隔，和定时汇总的统计，Close时停止
+	dropSummaryInterval time.Duration
+	dropStats           *dropStats
+
+	// 异步写日志配置，为空时同步写日志
+	async *asyncOption
+
+	// 输出配置，为空时根据logWriteToFile和stdout输出到文件和终端
+	sinks []Sink
+
+	// 异步写日志writer和日志文件writer，Close时关闭
+	asyncWriters []*asyncWriter
+	fileWriters  []rotateWriter
+
+	// WithContext 绑定的ctx
+	ctx context.Context
+
 	// zap底层Logger接口
 	fLogger *zap.Logger
 }
@@ -61,11 +103,9 @@ func New(opts ...Option) Logger {
 		log.Fatalln("init zap core error: ", err)
 	}
 
-	// 当 addCaller = true 并且 callerSkip > 0 才会记录文件名和行号
-	if z.addCaller && z.callerSkip > 0 {
-		z.fLogger = zap.New(core, zap.AddCaller(), zap.AddCallerSkip(z.callerSkip))
-	} else {
-		z.fLogger = zap.New(core)
+	z.fLogger = z.newZapLogger(core)
+	if z.dropStats != nil {
+		z.dropStats.start()
 	}
 
 	return z
@@ -73,7 +113,8 @@ func New(opts ...Option) Logger {
 
 // NewLogSugar zap log sugar语法糖
 // 支持Debug,Info,Error,Panic,Warn,Fatal等方法
-// 返回一个*zap.SugaredLogger
+// 返回一个*zap.SugaredLogger，它没有Close方法，不会启动定时输出丢弃日志汇总的goroutine，
+// 丢弃日志汇总在调用Sync时输出
 func NewLogSugar(opts ...Option) *zap.SugaredLogger {
 	z := defaultZapLogEntry()
 
@@ -84,14 +125,25 @@ func NewLogSugar(opts ...Option) *zap.SugaredLogger {
 		log.Fatalln("init zap core error: ", err)
 	}
 
+	z.fLogger = z.newZapLogger(core)
+
+	return z.fLogger.Sugar()
+}
+
+// newZapLogger 创建zap.Logger，并统计各级别日志行数
+func (z *zapLogWriter) newZapLogger(core zapcore.Core) *zap.Logger {
+	opts := []zap.Option{zap.Hooks(countLogLine)}
 	// 当 addCaller = true 并且 callerSkip > 0 才会记录文件名和行号
 	if z.addCaller && z.callerSkip > 0 {
-		z.fLogger = zap.New(core, zap.AddCaller(), zap.AddCallerSkip(z.callerSkip))
-	} else {
-		z.fLogger = zap.New(core)
+		opts = append(opts, zap.AddCaller(), zap.AddCallerSkip(z.callerSkip))
 	}
 
-	return z.fLogger.Sugar()
+	l := zap.New(&levelCore{Core: z.wrapDropCore(core), levels: z.levels}, opts...)
+	if z.name != "" {
+		l = l.Named(z.name)
+	}
+
+	return l
 }
 
 // defaultZapLogEntry create default zapLogWriter
@@ -106,6 +158,11 @@ func defaultZapLogEntry() *zapLogWriter {
 		stdout:      true, // 默认日志输出到stdout终端
 		jsonFormat:  true,
 		hostname:    defaultHostName,
+		levels:      newLevelRegistry(zapcore.InfoLevel),
+
+		dropSummaryInterval: defaultDropSummaryInterval,
+
+		redactKeys: DefaultRedactKeys,
 	}
 
 	return z
@@ -159,11 +216,84 @@ func (z *zapLogWriter) Fatal(ctx context.Context, msg string, fields ...interfac
 	z.fLogger.Fatal(msg, z.parseFields(ctx, fields)...)
 }
 
-// parseFields 解析map[string]interface{}中的字段到zap.Field
+// With 返回绑定了fields的子logger
+func (z *zapLogWriter) With(fields ...interface{}) Logger {
+	c := z.derive()
+	bound := parseArgs(make([]zap.Field, 0, len(fields)), fields)
+	if z.redactor != nil {
+		bound = z.redactor.fields(bound)
+	}
+
+	c.fLogger = z.fLogger.With(bound...)
+	return c
+}
+
+// Named 返回指定名称的子logger
+func (z *zapLogWriter) Named(name string) Logger {
+	c := z.derive()
+	c.fLogger = z.fLogger.Named(name)
+	if z.name == "" {
+		c.name = name
+	} else if name != "" {
+		c.name = z.name + "." + name
+	}
+
+	return c
+}
+
+// WithContext 返回绑定了ctx的子logger
+func (z *zapLogWriter) WithContext(ctx context.Context) Logger {
+	c := z.derive()
+	if z.ctx != nil {
+		ctx = mergeContext(ctx, z.ctx)
+	}
+
+	c.ctx = ctx
+	return c
+}
+
+// derive 复制一个子logger，日志文件、异步writer和丢弃日志统计只属于根logger
+// 子logger的Close只写入缓冲的日志，Rotate不切割日志文件
+func (z *zapLogWriter) derive() *zapLogWriter {
+	c := *z
+	c.fileWriters, c.asyncWriters, c.dropStats = nil, nil, nil
+	return &c
+}
+
+// parseFields 解析args和ctx中的字段到zap.Field
 func (z *zapLogWriter) parseFields(ctx context.Context, args []interface{}) []zap.Field {
-	fLen := len(args)
+	if z.ctx != nil {
+		ctx = mergeContext(ctx, z.ctx)
+	}
+
 	// 这里默认申请 len(args) + 20个容量，防止fields append过程中触发动态grow操作
-	fields := make([]zap.Field, 0, fLen+20)
+	fields := parseArgs(make([]zap.Field, 0, len(args)+20), args)
+
+	// 编码前对日志参数中的敏感字段脱敏，ctx中的字段不脱敏
+	if z.redactor != nil {
+		fields = z.redactor.fields(fields)
+	}
+
+	fields = append(fields, zap.String(CurHostname.String(), z.hostname))
+	// request_id 可能是一个数字，但建议使用uuid字符串
+	// 如果ctx中没有request_id，默认不记录，请求入口(http middleware,grpc拦截器)会为ctx分配request_id
+	// 其他场景使用 EnsureRequestID 为ctx分配，WithAutoRequestID(true) 开启每行日志自动生成
+	if reqID := ctx.Value(XRequestID); reqID != nil {
+		fields = append(fields, zap.Any(XRequestID.String(), reqID))
+	} else if z.autoRequestID && needRequestID(ctx) {
+		fields = append(fields, zap.String(XRequestID.String(), z.newRequestID()))
+	}
+
+	// local_time,client_ip,trace_id等字段由注册的ctx字段提取器提取
+	// 可以通过 RegisterContextField 添加自定义字段
+	fields = extractContextFields(ctx, fields)
+
+	return fields
+}
+
+// parseArgs 解析zap.Field,map[string]interface{}以及key-value对到zap.Field
+func parseArgs(fields []zap.Field, args []interface{}) []zap.Field {
+	fLen := len(args)
 	for i := 0; i < fLen; {
 		// This is a strongly-typed field. Consume it and move on.
 		if f, ok := args[i].(zap.Field); ok {
@@ -200,47 +330,113 @@ func (z *zapLogWriter) parseFields(ctx context.Context, args []interface{}) []za
 		i += 2
 	}
 
-	if curTime := ctx.Value(LocalTime); curTime == nil {
-		// add time_local 请求本地时间字段
-		fields = append(fields, zap.String(LocalTime.String(), time.Now().Format(tmFmtWithMS)))
-	}
+	return fields
+}
 
-	fields = append(fields, zap.String(CurHostname.String(), z.hostname))
-	// request_id 可能是一个数字，但建议使用uuid字符串
-	if reqID := ctx.Value(XRequestID); reqID != nil {
-		fields = append(fields, zap.Any(XRequestID.String(), reqID))
-	} else {
-		fields = append(fields, zap.String(XRequestID.String(), RndUUIDMd5()))
+// Sync 将缓冲的日志写入文件
+func (z *zapLogWriter) Sync() error {
+	return z.fLogger.Sync()
+}
+
+// Close 写入缓冲的日志，关闭异步写日志goroutine和日志文件
+// Close之后的日志同步写入，日志文件会被重新打开
+func (z *zapLogWriter) Close() error {
+	if z.dropStats != nil {
+		z.dropStats.stop()
 	}
 
-	// request ip 地址存在就记录
-	if ip := ctx.Value(ReqClientIP); ip != nil {
-		reqIP, _ := ip.(string)
-		fields = append(fields, zap.String(ReqClientIP.String(), reqIP))
+	err := z.fLogger.Sync()
+	for _, aw := range z.asyncWriters {
+		if cErr := aw.Close(); err == nil {
+			err = cErr
+		}
 	}
 
-	// request method 请求方法
-	if reqMethod := ctx.Value(RequestMethod); reqMethod != nil {
-		method, _ := reqMethod.(string)
-		fields = append(fields, zap.String(RequestMethod.String(), method))
+	for _, fw := range z.fileWriters {
+		if cErr := fw.Close(); err == nil {
+			err = cErr
+		}
 	}
 
-	// request uri 请求资源地址
-	if reqURI := ctx.Value(RequestURI); reqURI != nil {
-		uri, _ := reqURI.(string)
-		fields = append(fields, zap.String(RequestURI.String(), uri))
+	return err
+}
+
+// Rotate 切割所有日志文件，按时间切割的文件会重新打开当前文件
+// 适用于外部logrotate移动日志文件后
+func (z *zapLogWriter) Rotate() error {
+	var err error
+	for _, fw := range z.fileWriters {
+		if rErr := fw.Rotate(); err == nil {
+			err = rErr
+		}
 	}
 
-	return fields
+	return err
+}
+
+// SetLevel 运行时修改日志级别
+func (z *zapLogWriter) SetLevel(level zapcore.Level) {
+	z.levels.global.SetLevel(level)
+}
+
+// Level 返回当前日志级别
+func (z *zapLogWriter) Level() zapcore.Level {
+	return z.levels.global.Level()
+}
+
+// SetModuleLevels 运行时替换所有模块日志级别
+func (z *zapLogWriter) SetModuleLevels(levels map[string]zapcore.Level) {
+	z.levels.setModules(levels)
+}
+
+// ModuleLevels 返回所有模块日志级别
+func (z *zapLogWriter) ModuleLevels() map[string]zapcore.Level {
+	return z.levels.getModules()
 }
 
-// initCore 初始化zap core
+// initCore 初始化zap core，设置了sinks时每个sink创建一个core
 func (z *zapLogWriter) initCore() (zapcore.Core, error) {
+	// 日志级别由levelCore根据logger name过滤，底层core开启所有级别
+	z.levels.global.SetLevel(z.logLevel)
+	if z.redact {
+		z.redactor = newRedactor(z.redactKeys, z.redactMasks)
+	}
+	if len(z.sinks) > 0 {
+		return z.initSinks()
+	}
+
+	opts := make([]zapcore.WriteSyncer, 0, 2)
+	if z.logWriteToFile {
+		if z.logFilename == "" {
+			z.logFilename = filepath.Base(os.Args[0])
+		}
+
+		filename, err := z.filePath(z.logFilename)
+		if err != nil {
+			return nil, err
+		}
+
+		z.logFilename = filename
+		opts = append(opts, z.newFileWriter(filename))
+	}
+
+	if z.stdout {
+		opts = append(opts, stdoutWriter)
+	}
+
+	// 创建一个混合WriteSyncer
+	writerSyncer := z.wrapAsync(zapcore.NewMultiWriteSyncer(opts...))
+	return zapcore.NewCore(z.newEncoder(z.jsonFormat, z.enableColor), writerSyncer, allLevels), nil
+}
+
+// newEncoder 创建json或console格式的encoder
+func (z *zapLogWriter) newEncoder(jsonFormat bool, enableColor bool) zapcore.Encoder {
 	// encoder config
 	encoderConf := zapcore.EncoderConfig{
 		TimeKey:        "time_local", // 本地时间字段
 		LevelKey:       "level",
 		MessageKey:     "msg",
+		NameKey:        "logger", // logger name，未设置名称时不输出
 		CallerKey:      "caller_line",
 		LineEnding:     zapcore.DefaultLineEnding,
 		EncodeTime:     zapcore.ISO8601TimeEncoder, // ISO8601 UTC 时间格式
@@ -250,55 +446,79 @@ func (z *zapLogWriter) initCore() (zapcore.Core, error) {
 	}
 
 	// 是否染色
-	if z.enableColor {
+	if enableColor {
 		encoderConf.EncodeLevel = zapcore.LowercaseColorLevelEncoder
 	} else {
 		encoderConf.EncodeLevel = zapcore.LowercaseLevelEncoder // 小写编码器
 	}
 
-	opts := make([]zapcore.WriteSyncer, 0, 2)
-	if z.logWriteToFile {
-		if z.logFilename == "" {
-			z.logFilename = filepath.Base(os.Args[0])
-		}
+	// json格式化日志
+	if jsonFormat {
+		return zapcore.NewJSONEncoder(encoderConf)
+	}
 
-		if z.logDir == "" {
-			z.logFilename = filepath.Join(os.TempDir(), z.logFilename) // 默认日志文件名称
-		} else {
-			if !z.checkPathExist(z.logDir) {
-				if err := os.MkdirAll(z.logDir, 0755); err != nil {
-					return nil, err
-				}
-			}
+	return zapcore.NewConsoleEncoder(encoderConf)
+}
+
+// filePath 返回日志文件路径，相对路径的文件放在logDir中，logDir为空时放在临时目录中
+func (z *zapLogWriter) filePath(filename string) (string, error) {
+	if filepath.IsAbs(filename) {
+		return filename, nil
+	}
 
-			z.logFilename = filepath.Join(z.logDir, z.logFilename)
+	if z.logDir == "" {
+		return filepath.Join(os.TempDir(), filename), nil // 默认日志文件名称
+	}
+
+	if !z.checkPathExist(z.logDir) {
+		if err := os.MkdirAll(z.logDir, 0755); err != nil {
+			return "", err
 		}
+	}
+
+	return filepath.Join(z.logDir, filename), nil
+}
 
-		// 日志最低级别设置
-		syncWriter := zapcore.AddSync(&lumberjack.Logger{
-			Filename:  z.logFilename, // ⽇志⽂件路径
-			MaxSize:   z.maxSize,     // 单位为MB,默认为512MB
-			MaxAge:    z.maxAge,      // 文件最多保存多少天
-			LocalTime: true,          // 采用本地时间
-			Compress:  z.compress,    // 是否压缩日志
-		})
+// rotateWriter 可切割的日志文件writer
+type rotateWriter interface {
+	io.WriteCloser
+	Rotate() error
+}
 
-		opts = append(opts, syncWriter)
+// newFileWriter 创建日志文件writer
+// 文件名包含%Y,%m,%d,%H,%M时间占位符或设置了rotateTime时按时间切割，否则按大小切割
+func (z *zapLogWriter) newFileWriter(filename string) zapcore.WriteSyncer {
+	if z.rotateTime > 0 && !strings.Contains(filename, "%") {
+		filename = defaultFilenamePattern(filename, z.rotateTime)
 	}
 
-	if z.stdout {
-		opts = append(opts, zapcore.AddSync(os.Stdout))
+	var fw rotateWriter
+	if strings.Contains(filename, "%") {
+		fw = newTimeWriter(filename, z.rotateTime, z.maxAge, z.maxBackups)
+	} else {
+		fw = &lumberjack.Logger{
+			Filename:   filename,     // ⽇志⽂件路径
+			MaxSize:    z.maxSize,    // 单位为MB,默认为512MB
+			MaxAge:     z.maxAge,     // 文件最多保存多少天
+			MaxBackups: z.maxBackups, // 最多保留多少个旧文件
+			LocalTime:  true,         // 采用本地时间
+			Compress:   z.compress,   // 是否压缩日志
+		}
 	}
 
-	// 创建一个混合WriteSyncer
-	writerSyncer := zapcore.NewMultiWriteSyncer(opts...)
+	z.fileWriters = append(z.fileWriters, fw)
+	return zapcore.AddSync(fw)
+}
 
-	// json格式化日志
-	if z.jsonFormat {
-		return zapcore.NewCore(zapcore.NewJSONEncoder(encoderConf), writerSyncer, z.logLevel), nil
+// wrapAsync 开启异步写日志时，包装为asyncWriter
+func (z *zapLogWriter) wrapAsync(ws zapcore.WriteSyncer) zapcore.WriteSyncer {
+	if z.async == nil {
+		return ws
 	}
 
-	return zapcore.NewCore(zapcore.NewConsoleEncoder(encoderConf), writerSyncer, z.logLevel), nil
+	aw := newAsyncWriter(ws, z.async.queueSize, z.async.policy)
+	z.asyncWriters = append(z.asyncWriters, aw)
+	return aw
 }
 
 // checkPathExist check file or path exist
@@ -314,3 +534,12 @@ func (z *zapLogWriter) checkPathExist(path string) bool {
 
 	return false
 }
+
+// newRequestID 生成request_id
+func (z *zapLogWriter) newRequestID() string {
+	if z.idGenerator != nil {
+		return z.idGenerator.NewID()
+	}
+
+	return NewRequestID()
+}
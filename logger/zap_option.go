@@ -1,6 +1,9 @@
 package logger
 
 import (
+	"time"
+
+	"github.com/go-god/msa/idgen"
 	"go.uber.org/zap/zapcore"
 )
 
@@ -28,6 +31,23 @@ func WithMaxSize(size int) Option {
 	}
 }
 
+// WithMaxBackups 保留的旧日志文件数，默认不限制
+func WithMaxBackups(n int) Option {
+	return func(z *zapLogWriter) {
+		z.maxBackups = n
+	}
+}
+
+// WithRotateTime 按时间切割日志，最大为24小时
+// 文件名没有时间占位符时，在后缀前添加，比如按小时切割 app.log 的文件名为 app-2022081510.log
+// 文件名也可以直接使用时间占位符，比如 WithLogFilename("app-%Y%m%d.log")，默认按天切割
+// 按时间切割时只有WithMaxAge和WithMaxBackups生效，WithMaxSize和WithCompress不生效
+func WithRotateTime(d time.Duration) Option {
+	return func(z *zapLogWriter) {
+		z.rotateTime = d
+	}
+}
+
 // WithCompress 日志是否压缩
 func WithCompress(b bool) Option {
 	return func(z *zapLogWriter) {
@@ -66,6 +86,22 @@ func WithLogLevel(level zapcore.Level) Option {
 	}
 }
 
+// WithModuleLevel 设置模块日志级别，module为logger name，对子模块同样生效
+func WithModuleLevel(module string, level zapcore.Level) Option {
+	return func(z *zapLogWriter) {
+		levels := z.levels.getModules()
+		levels[module] = level
+		z.levels.setModules(levels)
+	}
+}
+
+// WithName 设置logger name，作为模块名用于模块日志级别
+func WithName(name string) Option {
+	return func(z *zapLogWriter) {
+		z.name = name
+	}
+}
+
 // WithWriteToFile 设置日志是否写入文件中
 func WithWriteToFile(b bool) Option {
 	return func(z *zapLogWriter) {
@@ -114,3 +150,87 @@ func WithHostname(hostname string) Option {
 		z.hostname = hostname
 	}
 }
+
+// WithAutoRequestID ctx中没有request_id时是否每行日志自动生成一个，默认为false
+// 不开启时，没有request_id的日志不记录x-request-id字段
+func WithAutoRequestID(b bool) Option {
+	return func(z *zapLogWriter) {
+		z.autoRequestID = b
+	}
+}
+
+// WithIDGenerator 自定义自动生成request_id的generator，例如 idgen.UUIDv7 或 idgen.NewSnowflake
+func WithIDGenerator(g idgen.IDGenerator) Option {
+	return func(z *zapLogWriter) {
+		z.idGenerator = g
+	}
+}
+
+// WithSampling 日志采样，每个tick内相同级别和内容的日志，前first条记录，之后每thereafter条记录一条
+// thereafter为0时，超过first条的日志全部丢弃，Error及以上级别的日志不会被采样
+func WithSampling(tick time.Duration, first, thereafter int) Option {
+	return func(z *zapLogWriter) {
+		if tick > 0 && first > 0 {
+			z.sampling = &samplingOption{tick: tick, first: first, thereafter: thereafter}
+		}
+	}
+}
+
+// WithRateLimit 按日志内容限流，每个interval内相同内容的日志最多记录limit条，Error及以上级别的日志不会被限流
+func WithRateLimit(limit int, interval time.Duration) Option {
+	return func(z *zapLogWriter) {
+		if limit > 0 && interval > 0 {
+			z.rateLimit = &rateLimitOption{limit: limit, interval: interval}
+		}
+	}
+}
+
+// WithDropSummary 被采样或限流丢弃的日志汇总输出间隔，默认1分钟，<=0不输出汇总日志
+// 汇总由定时goroutine输出，Close时停止
+// 丢弃的行数同时记录在 msa_log_dropped_total{reason,level} 指标中
+func WithDropSummary(interval time.Duration) Option {
+	return func(z *zapLogWriter) {
+		z.dropSummaryInterval = interval
+	}
+}
+
+// WithAsync 异步写日志，日志先写入长度为queueSize的队列，队列满时按policy处理
+// 程序退出前需要调用 Sync 或 Close，否则队列中的日志可能丢失
+func WithAsync(queueSize int, policy OverflowPolicy) Option {
+	return func(z *zapLogWriter) {
+		z.async = &asyncOption{queueSize: queueSize, policy: policy}
+	}
+}
+
+// WithSinks 设置多个日志输出，每个输出可以设置不同的级别和格式
+// 设置后 WithWriteToFile,WithStdout,WithEnableColor 不再生效
+func WithSinks(sinks ...Sink) Option {
+	return func(z *zapLogWriter) {
+		z.sinks = sinks
+	}
+}
+
+// WithRedact 是否对敏感字段脱敏，默认不开启
+// 字段名包含敏感字段、结构体字段tag为 log:"redact" 或值匹配 ValueMask 时脱敏
+func WithRedact(b bool) Option {
+	return func(z *zapLogWriter) {
+		z.redact = b
+	}
+}
+
+// WithRedactKeys 设置敏感字段并开启脱敏，字段名拆分后的词包含其中之一(不区分大小写)时脱敏，
+// 默认为 DefaultRedactKeys
+func WithRedactKeys(keys ...string) Option {
+	return func(z *zapLogWriter) {
+		z.redact = true
+		z.redactKeys = keys
+	}
+}
+
+// WithRedactValues 设置按值脱敏规则并开启脱敏，默认不按值脱敏，比如 WithRedactValues(DefaultValueMasks...)
+func WithRedactValues(masks ...ValueMask) Option {
+	return func(z *zapLogWriter) {
+		z.redact = true
+		z.redactMasks = masks
+	}
+}
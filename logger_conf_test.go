@@ -0,0 +1,34 @@
+package msa
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-god/msa/config"
+	"github.com/go-god/msa/logger"
+)
+
+// TestEngineLoggerConfig test the default logger is created from logger section and options.
+func TestEngineLoggerConfig(t *testing.T) {
+	dir := t.TempDir()
+	conf := []byte("logger:\n  level: warn\n  dir: " + dir + "\n  filename: conf.log\n  stdout: false\n" +
+		"  modules:\n    grpc: error\n")
+	if err := os.WriteFile(filepath.Join(dir, "app.yaml"), conf, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	New(WithConfigInterface(config.New(config.WithConfigDir(dir), config.WithConfigFile("app.yaml"))),
+		WithLogger(logger.WithLogFilename("override.log")))
+	defer logger.Close()
+
+	logger.Warn(context.Background(), "logger from config")
+	if logger.GetLevel() != "warn" || logger.GetModuleLevels()["grpc"] != "error" {
+		t.Fatalf("log level: %s modules: %v", logger.GetLevel(), logger.GetModuleLevels())
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "override.log")); err != nil {
+		t.Fatal(err)
+	}
+}
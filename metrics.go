@@ -0,0 +1,47 @@
+package msa
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-god/gdi"
+
+	"github.com/go-god/msa/metrics"
+)
+
+// engine metrics in metrics.DefaultRegistry
+var (
+	componentDuration = metrics.NewHistogram("msa_component_duration_seconds",
+		"Duration of component lifecycle actions.", nil, "component", "action")
+	engineState = metrics.NewGauge("msa_engine_state",
+		"Engine lifecycle state, the current state is 1.", "state")
+	configReloads = metrics.NewCounter("msa_config_reload_total",
+		"Total config reloads by result.", "result")
+)
+
+// observeComponent record the duration of component action,eg: init,start,stop
+func observeComponent(obj *gdi.Object, action string, fn func()) {
+	start := time.Now()
+	fn()
+	componentDuration.With(objectName(obj), action).Observe(time.Since(start).Seconds())
+}
+
+// observeState set engine state gauge
+func observeState(s State) {
+	for state, name := range stateNames {
+		if state == s {
+			engineState.With(name).Set(1)
+		} else {
+			engineState.With(name).Set(0)
+		}
+	}
+}
+
+// objectName return inject name or the object type
+func objectName(obj *gdi.Object) string {
+	if obj.Name != "" {
+		return obj.Name
+	}
+
+	return fmt.Sprintf("%T", obj.Value)
+}
@@ -0,0 +1,221 @@
+// Package metrics counters, gauges and histograms with labels,
+// they can be exported by prometheus text format.
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefBuckets default histogram buckets in seconds
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// metricType prometheus metric type
+type metricType string
+
+const (
+	counterType   metricType = "counter"
+	gaugeType     metricType = "gauge"
+	histogramType metricType = "histogram"
+)
+
+// labelSep separator of label values in series key
+const labelSep = "\xff"
+
+// desc metric description
+type desc struct {
+	name   string
+	help   string
+	typ    metricType
+	labels []string
+}
+
+// vec metric series by label values
+type vec struct {
+	desc
+	mu     sync.RWMutex
+	series map[string]*series
+	newFn  func() *series
+}
+
+// series a metric with label values
+type series struct {
+	mu          sync.Mutex
+	labelValues []string
+	value       float64
+	buckets     []float64 // histogram upper bounds
+	counts      []uint64  // histogram cumulative counts by buckets
+	count       uint64    // histogram observations count
+}
+
+// with return series of label values,it panics if the length of values is not equal to labels
+func (v *vec) with(values ...string) *series {
+	if len(values) != len(v.labels) {
+		panic("metrics: " + v.name + " label values length mismatch")
+	}
+
+	key := strings.Join(values, labelSep)
+	v.mu.RLock()
+	s, ok := v.series[key]
+	v.mu.RUnlock()
+	if ok {
+		return s
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if s, ok = v.series[key]; ok {
+		return s
+	}
+
+	s = v.newFn()
+	s.labelValues = append([]string(nil), values...)
+	v.series[key] = s
+	return s
+}
+
+// sortedSeries return series sorted by label values
+func (v *vec) sortedSeries() []*series {
+	v.mu.RLock()
+	keys := make([]string, 0, len(v.series))
+	for k := range v.series {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	list := make([]*series, 0, len(keys))
+	for _, k := range keys {
+		list = append(list, v.series[k])
+	}
+	v.mu.RUnlock()
+
+	return list
+}
+
+// CounterVec counter metrics with labels
+type CounterVec struct {
+	v *vec
+}
+
+// With return counter of label values
+func (c *CounterVec) With(labelValues ...string) *Counter {
+	return &Counter{s: c.v.with(labelValues...)}
+}
+
+// Counter monotonically increasing value
+type Counter struct {
+	s *series
+}
+
+// Inc add 1
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add add delta,it panics if delta is negative
+func (c *Counter) Add(delta float64) {
+	if delta < 0 {
+		panic("metrics: counter cannot decrease")
+	}
+
+	c.s.mu.Lock()
+	c.s.value += delta
+	c.s.mu.Unlock()
+}
+
+// Value return current value
+func (c *Counter) Value() float64 {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+	return c.s.value
+}
+
+// GaugeVec gauge metrics with labels
+type GaugeVec struct {
+	v *vec
+}
+
+// With return gauge of label values
+func (g *GaugeVec) With(labelValues ...string) *Gauge {
+	return &Gauge{s: g.v.with(labelValues...)}
+}
+
+// Gauge value which can go up and down
+type Gauge struct {
+	s *series
+}
+
+// Set set value
+func (g *Gauge) Set(value float64) {
+	g.s.mu.Lock()
+	g.s.value = value
+	g.s.mu.Unlock()
+}
+
+// Inc add 1
+func (g *Gauge) Inc() {
+	g.Add(1)
+}
+
+// Dec sub 1
+func (g *Gauge) Dec() {
+	g.Add(-1)
+}
+
+// Add add delta
+func (g *Gauge) Add(delta float64) {
+	g.s.mu.Lock()
+	g.s.value += delta
+	g.s.mu.Unlock()
+}
+
+// Value return current value
+func (g *Gauge) Value() float64 {
+	g.s.mu.Lock()
+	defer g.s.mu.Unlock()
+	return g.s.value
+}
+
+// HistogramVec histogram metrics with labels
+type HistogramVec struct {
+	v *vec
+}
+
+// With return histogram of label values
+func (h *HistogramVec) With(labelValues ...string) *Histogram {
+	return &Histogram{s: h.v.with(labelValues...)}
+}
+
+// Histogram count observations in buckets
+type Histogram struct {
+	s *series
+}
+
+// Observe add an observation
+func (h *Histogram) Observe(value float64) {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	for i, upper := range h.s.buckets {
+		if value <= upper {
+			h.s.counts[i]++
+		}
+	}
+
+	h.s.count++
+	h.s.value += value
+}
+
+// Count return observations count
+func (h *Histogram) Count() uint64 {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	return h.s.count
+}
+
+// Sum return observations sum
+func (h *Histogram) Sum() float64 {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	return h.s.value
+}
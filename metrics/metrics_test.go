@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestWritePrometheus test prometheus text format.
+func TestWritePrometheus(t *testing.T) {
+	r := NewRegistry()
+	requests := r.NewCounter("http_requests_total", "Total http requests.", "method", "code")
+	requests.With("GET", "200").Inc()
+	requests.With("GET", "200").Add(2)
+	requests.With("POST", "500").Inc()
+
+	r.NewGauge("temperature", "Current \"temperature\".").With().Set(-1.5)
+	latency := r.NewHistogram("latency_seconds", "Request latency.", []float64{0.1, 1}, "path")
+	latency.With(`/a"b`).Observe(0.05)
+	latency.With(`/a"b`).Observe(0.5)
+	latency.With(`/a"b`).Observe(3)
+
+	if r.NewCounter("http_requests_total", "Total http requests.", "method", "code") == nil {
+		t.Fatal("register same counter again")
+	}
+
+	var buf bytes.Buffer
+	if err := r.WritePrometheus(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `# HELP http_requests_total Total http requests.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",code="200"} 3
+http_requests_total{method="POST",code="500"} 1
+# HELP latency_seconds Request latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{path="/a\"b",le="0.1"} 1
+latency_seconds_bucket{path="/a\"b",le="1"} 2
+latency_seconds_bucket{path="/a\"b",le="+Inf"} 3
+latency_seconds_sum{path="/a\"b"} 3.55
+latency_seconds_count{path="/a\"b"} 3
+# HELP temperature Current "temperature".
+# TYPE temperature gauge
+temperature -1.5
+`
+	if buf.String() != expected {
+		t.Fatalf("prometheus text:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ContentType prometheus text format content type
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var (
+	helpReplacer  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+// Handler return http handler which serves prometheus text format
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		_ = r.WritePrometheus(w)
+	})
+}
+
+// WritePrometheus write all metrics in prometheus text format
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, v := range r.sortedMetrics() {
+		list := v.sortedSeries()
+		if len(list) == 0 {
+			continue
+		}
+
+		bw.WriteString("# HELP " + v.name + " " + helpReplacer.Replace(v.help) + "\n")
+		bw.WriteString("# TYPE " + v.name + " " + string(v.typ) + "\n")
+		for _, s := range list {
+			s.mu.Lock()
+			if v.typ == histogramType {
+				for i, upper := range s.buckets {
+					writeSample(bw, v.name+"_bucket", v.labels, s.labelValues, "le", formatFloat(upper),
+						float64(s.counts[i]))
+				}
+
+				writeSample(bw, v.name+"_bucket", v.labels, s.labelValues, "le", "+Inf", float64(s.count))
+				writeSample(bw, v.name+"_sum", v.labels, s.labelValues, "", "", s.value)
+				writeSample(bw, v.name+"_count", v.labels, s.labelValues, "", "", float64(s.count))
+			} else {
+				writeSample(bw, v.name, v.labels, s.labelValues, "", "", s.value)
+			}
+			s.mu.Unlock()
+		}
+	}
+
+	return bw.Flush()
+}
+
+// writeSample write a sample line,extraLabel is used by histogram le label
+func writeSample(w *bufio.Writer, name string, labels, values []string, extraLabel, extraValue string, value float64) {
+	w.WriteString(name)
+	if len(labels) > 0 || extraLabel != "" {
+		w.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+
+			w.WriteString(l + `="` + labelReplacer.Replace(values[i]) + `"`)
+		}
+
+		if extraLabel != "" {
+			if len(labels) > 0 {
+				w.WriteByte(',')
+			}
+
+			w.WriteString(extraLabel + `="` + extraValue + `"`)
+		}
+
+		w.WriteByte('}')
+	}
+
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+// formatFloat format float in prometheus format
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
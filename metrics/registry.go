@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// DefaultRegistry default metrics registry,msa engine metrics are registered in it
+var DefaultRegistry = NewRegistry()
+
+// nameRegexp valid metric and label name
+var nameRegexp = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// Registry metrics registry
+type Registry struct {
+	mu      sync.RWMutex
+	metrics map[string]*vec
+}
+
+// NewRegistry create a metrics registry
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]*vec, 20)}
+}
+
+// NewCounter register counter,if the same counter exists it will be returned
+func (r *Registry) NewCounter(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{v: r.register(desc{name: name, help: help, typ: counterType, labels: labels}, nil)}
+}
+
+// NewGauge register gauge,if the same gauge exists it will be returned
+func (r *Registry) NewGauge(name, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{v: r.register(desc{name: name, help: help, typ: gaugeType, labels: labels}, nil)}
+}
+
+// NewHistogram register histogram,if buckets is empty DefBuckets will be used.
+// If the same histogram exists it will be returned.
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &HistogramVec{v: r.register(desc{name: name, help: help, typ: histogramType, labels: labels}, buckets)}
+}
+
+// register metric,it panics if the name is invalid or
+// the metric exists with a different type or labels
+func (r *Registry) register(d desc, buckets []float64) *vec {
+	if !nameRegexp.MatchString(d.name) {
+		panic("metrics: invalid metric name " + d.name)
+	}
+
+	for _, l := range d.labels {
+		if !nameRegexp.MatchString(l) || l == "le" {
+			panic("metrics: invalid label name " + l)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if v, ok := r.metrics[d.name]; ok {
+		if v.typ != d.typ || fmt.Sprint(v.labels) != fmt.Sprint(d.labels) {
+			panic("metrics: " + d.name + " registered with different type or labels")
+		}
+
+		return v
+	}
+
+	v := &vec{
+		desc:   d,
+		series: make(map[string]*series),
+		newFn: func() *series {
+			return &series{buckets: buckets, counts: make([]uint64, len(buckets))}
+		},
+	}
+
+	r.metrics[d.name] = v
+	return v
+}
+
+// sortedMetrics return metrics sorted by name
+func (r *Registry) sortedMetrics() []*vec {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := make([]*vec, 0, len(r.metrics))
+	for _, v := range r.metrics {
+		list = append(list, v)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].name < list[j].name
+	})
+
+	return list
+}
+
+// NewCounter register counter in DefaultRegistry
+func NewCounter(name, help string, labels ...string) *CounterVec {
+	return DefaultRegistry.NewCounter(name, help, labels...)
+}
+
+// NewGauge register gauge in DefaultRegistry
+func NewGauge(name, help string, labels ...string) *GaugeVec {
+	return DefaultRegistry.NewGauge(name, help, labels...)
+}
+
+// NewHistogram register histogram in DefaultRegistry
+func NewHistogram(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return DefaultRegistry.NewHistogram(name, help, buckets, labels...)
+}
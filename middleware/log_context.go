@@ -0,0 +1,210 @@
+// Package middleware net/http middleware
+package middleware
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-god/msa/idgen"
+	"github.com/go-god/msa/logger"
+)
+
+const (
+	// RequestIDHeader request id header,it is read from request and echoed in response
+	RequestIDHeader = "X-Request-ID"
+
+	// maxRequestIDLen max length of request id header
+	maxRequestIDLen = 128
+
+	// tmFmtWithMS local time format
+	tmFmtWithMS = "2006-01-02 15:04:05.999"
+)
+
+// LogContext return a middleware which stores the logger fields in the request context:
+// logger.XRequestID, logger.ReqClientIP, logger.RequestMethod, logger.RequestURI and logger.LocalTime.
+// The request id is read from X-Request-ID header,the trace id of context or generated,
+// and it is echoed in the response. The header is only used if it has at most 128 characters
+// of [A-Za-z0-9._-].
+// The client ip is read from X-Forwarded-For and X-Real-IP only if WithTrustProxy(true) is set.
+// After the request is served an access log line is written by logger.Logger.
+func LogContext(opts ...Option) func(http.Handler) http.Handler {
+	o := &logContextOption{
+		accessLog:   true,
+		idGenerator: idgen.GeneratorFunc(logger.NewRequestID),
+		skipPaths:   make(map[string]bool),
+	}
+
+	for _, fn := range opts {
+		fn(o)
+	}
+
+	if o.accessLog && o.logger == nil {
+		o.logger = logger.DefaultLogger()
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			// the trace id is used as request id if X-Request-ID is absent,
+			// so the logs of a request are correlated across services.
+			reqID := r.Header.Get(RequestIDHeader)
+			if !validRequestID(reqID) {
+				reqID = ""
+			}
+
+			if reqID == "" {
+				reqID, _ = r.Context().Value(logger.TraceID).(string)
+			}
+
+			if reqID == "" {
+				reqID = o.idGenerator.NewID()
+			}
+
+			w.Header().Set(RequestIDHeader, reqID)
+
+			ctx := r.Context()
+			ctx = context.WithValue(ctx, logger.XRequestID, reqID)
+			ctx = context.WithValue(ctx, logger.ReqClientIP, ClientIP(r, o.trustProxy))
+			ctx = context.WithValue(ctx, logger.RequestMethod, r.Method)
+			ctx = context.WithValue(ctx, logger.RequestURI, r.RequestURI)
+			ctx = context.WithValue(ctx, logger.LocalTime, start.Format(tmFmtWithMS))
+			r = r.WithContext(ctx)
+
+			if !o.accessLog || o.skipPaths[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			o.logger.Info(ctx, "access log", map[string]interface{}{
+				"status":     rec.status,
+				"bytes":      rec.bytes,
+				"cost_time":  time.Since(start).Seconds(),
+				"user_agent": r.UserAgent(),
+				"referer":    r.Referer(),
+				"host":       r.Host,
+				"proto":      r.Proto,
+			})
+		})
+	}
+}
+
+// validRequestID check request id is not empty,
+// has at most maxRequestIDLen characters and only contains [A-Za-z0-9._-]
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '.' || c == '_' || c == '-') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ClientIP return client ip of request,
+// if trustProxy is true the first ip of X-Forwarded-For or X-Real-IP header is used.
+func ClientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			if ip := strings.TrimSpace(strings.Split(xff, ",")[0]); ip != "" {
+				return ip
+			}
+		}
+
+		if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); ip != "" {
+			return ip
+		}
+	}
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return ip
+}
+
+// statusRecorder record response status code and bytes
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+// WriteHeader record status code
+func (s *statusRecorder) WriteHeader(code int) {
+	if !s.wroteHeader {
+		s.status = code
+		s.wroteHeader = true
+	}
+
+	s.ResponseWriter.WriteHeader(code)
+}
+
+// Write record bytes
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	s.wroteHeader = true
+	n, err := s.ResponseWriter.Write(b)
+	s.bytes += n
+	return n, err
+}
+
+// Flush implements http.Flusher
+func (s *statusRecorder) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker,eg: websocket upgrade
+func (s *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := s.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not implement http.Hijacker")
+	}
+
+	return h.Hijack()
+}
+
+// Push implements http.Pusher
+func (s *statusRecorder) Push(target string, opts *http.PushOptions) error {
+	if p, ok := s.ResponseWriter.(http.Pusher); ok {
+		return p.Push(target, opts)
+	}
+
+	return http.ErrNotSupported
+}
+
+// ReadFrom implements io.ReaderFrom,so sendfile is still used by the wrapped writer
+func (s *statusRecorder) ReadFrom(r io.Reader) (int64, error) {
+	s.wroteHeader = true
+	var n int64
+	var err error
+	if rf, ok := s.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = rf.ReadFrom(r)
+	} else {
+		n, err = io.Copy(s.ResponseWriter, r)
+	}
+
+	s.bytes += int(n)
+	return n, err
+}
+
+// Unwrap return the wrapped writer for http.ResponseController
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
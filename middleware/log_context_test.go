@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-god/msa/logger"
+)
+
+// testLogger record the context and fields of Info
+type testLogger struct {
+	logger.Logger
+	ctx    context.Context
+	fields []interface{}
+}
+
+func (l *testLogger) Info(ctx context.Context, msg string, fields ...interface{}) {
+	l.ctx = ctx
+	l.fields = fields
+}
+
+// TestLogContext test logger fields and access log.
+func TestLogContext(t *testing.T) {
+	l := &testLogger{}
+	var reqCtx context.Context
+	h := LogContext(WithLogger(l), WithTrustProxy(true))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqCtx = r.Context()
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/users?id=1", nil)
+	req.Header.Set("X-Forwarded-For", "10.0.0.1, 10.0.0.2")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	reqID := rec.Header().Get(RequestIDHeader)
+	if reqID == "" || reqCtx.Value(logger.XRequestID) != reqID {
+		t.Fatalf("request id: %s ctx: %v", reqID, reqCtx.Value(logger.XRequestID))
+	}
+
+	if reqCtx.Value(logger.ReqClientIP) != "10.0.0.1" || reqCtx.Value(logger.RequestMethod) != http.MethodPost ||
+		reqCtx.Value(logger.RequestURI) != "/users?id=1" {
+		t.Fatalf("request context: %v %v %v", reqCtx.Value(logger.ReqClientIP),
+			reqCtx.Value(logger.RequestMethod), reqCtx.Value(logger.RequestURI))
+	}
+
+	if l.ctx == nil || l.fields[0].(map[string]interface{})["status"] != http.StatusCreated {
+		t.Fatalf("access log fields: %v", l.fields)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "abc")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Header().Get(RequestIDHeader) != "abc" {
+		t.Fatalf("echo request id: %s", rec.Header().Get(RequestIDHeader))
+	}
+
+	for _, id := range []string{"abc\r\nx: 1", "<script>", strings.Repeat("a", maxRequestIDLen+1)} {
+		req = httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header[RequestIDHeader] = []string{id}
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if got := rec.Header().Get(RequestIDHeader); got == id || got == "" || reqCtx.Value(logger.XRequestID) != got {
+			t.Fatalf("invalid request id %q is not replaced: %q", id, got)
+		}
+	}
+}
+
+// TestLogContextUntrustedProxy test X-Forwarded-For is ignored by default.
+func TestLogContextUntrustedProxy(t *testing.T) {
+	var clientIP interface{}
+	h := LogContext(WithAccessLog(false))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientIP = r.Context().Value(logger.ReqClientIP)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.168.1.2:1234"
+	req.Header.Set("X-Forwarded-For", "10.0.0.1")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if clientIP != "192.168.1.2" {
+		t.Fatalf("client ip: %v", clientIP)
+	}
+}
+
+// TestLogContextUpgrade test a connection can be upgraded through the middleware.
+func TestLogContextUpgrade(t *testing.T) {
+	l := &testLogger{}
+	h := LogContext(WithLogger(l))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the deadline is set on the wrapped writer by Unwrap
+		if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(time.Second)); err != nil {
+			t.Error(err)
+		}
+
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: echo\r\nConnection: Upgrade\r\n\r\n")
+		rw.Flush()
+		line, _ := rw.ReadString('\n')
+		rw.WriteString(line)
+		rw.Flush()
+	}))
+
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	conn, err := net.Dial("tcp", ts.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	conn.Write([]byte("GET / HTTP/1.1\r\nHost: test\r\nUpgrade: echo\r\nConnection: Upgrade\r\n\r\n"))
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil || resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("upgrade response: %v error: %v", resp, err)
+	}
+
+	conn.Write([]byte("ping\n"))
+	if line, err := br.ReadString('\n'); err != nil || line != "ping\n" {
+		t.Fatalf("echo: %q error: %v", line, err)
+	}
+}
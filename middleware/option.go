@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"github.com/go-god/msa/idgen"
+	"github.com/go-god/msa/logger"
+)
+
+// Option LogContext option
+type Option func(o *logContextOption)
+
+type logContextOption struct {
+	logger      logger.Logger     // access log logger,default is logger.DefaultLogger() when the middleware is built
+	accessLog   bool              // write access log,default true
+	trustProxy  bool              // read client ip from X-Forwarded-For and X-Real-IP,default false
+	idGenerator idgen.IDGenerator // generate request id,default logger.NewRequestID
+	skipPaths   map[string]bool   // paths without access log,eg: /healthz
+}
+
+// WithLogger set access log logger
+func WithLogger(l logger.Logger) Option {
+	return func(o *logContextOption) {
+		o.logger = l
+	}
+}
+
+// WithAccessLog set whether to write access log
+func WithAccessLog(b bool) Option {
+	return func(o *logContextOption) {
+		o.accessLog = b
+	}
+}
+
+// WithTrustProxy set whether to read client ip from X-Forwarded-For and X-Real-IP headers,
+// only enable it behind a trusted reverse proxy,otherwise the client ip can be spoofed.
+func WithTrustProxy(b bool) Option {
+	return func(o *logContextOption) {
+		o.trustProxy = b
+	}
+}
+
+// WithIDGenerator set request id generator,eg: idgen.UUIDv7
+func WithIDGenerator(g idgen.IDGenerator) Option {
+	return func(o *logContextOption) {
+		o.idGenerator = g
+	}
+}
+
+// WithSkipPaths set paths without access log
+func WithSkipPaths(paths ...string) Option {
+	return func(o *logContextOption) {
+		for _, p := range paths {
+			o.skipPaths[p] = true
+		}
+	}
+}
@@ -2,14 +2,20 @@ package msa
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
+	"sync"
 	"time"
 
 	"github.com/go-god/gdi"
 	"github.com/go-god/gdi/factory"
 	"github.com/go-god/msa/config"
+	"github.com/go-god/msa/health"
+	"github.com/go-god/msa/logger"
 	"github.com/go-god/msa/provides"
 )
 
@@ -23,21 +29,48 @@ type starter interface {
 	Start() error
 }
 
+// engineSetter components which need the engine,eg: resolve inject groups or health state
+type engineSetter interface {
+	SetEngine(e *Engine)
+}
+
 // stoppable stop interface
 type stoppable interface {
 	Stop()
 }
 
+// Drainer drain interface,servers can implement it to stop accepting new work
+// and finish in-flight requests before Stop is called.
+// ctx is canceled when the graceful wait time is exceeded.
+type Drainer interface {
+	Drain(ctx context.Context) error
+}
+
 // Engine application engine
 type Engine struct {
-	interruptSignals []os.Signal         // interrupt signals
-	gracefulWait     time.Duration       // graceful exit time
-	signal           chan os.Signal      // recv interrupt signals
-	injectValues     []*gdi.Object       // inject objects
-	injector         gdi.Injector        // dip inject interface
-	invokeFunc       []interface{}       // invoke func
-	providers        []provides.Provider // all provides
-	stopCh           chan struct{}       // stop chan,if you call Stop() application will exit
+	interruptSignals []os.Signal                  // interrupt signals
+	rotateSignals    []os.Signal                  // rotate the default logger files
+	gracefulWait     time.Duration                // graceful exit time
+	drainDelay       time.Duration                // wait readiness propagation before draining
+	signal           chan os.Signal               // recv interrupt signals
+	injectValues     []*gdi.Object                // inject objects
+	objectSources    map[*gdi.Object]ObjectSource // inject objects source
+	injector         gdi.Injector                 // dip inject interface
+	injectType       factory.InjectType           // inject type of injector
+	invokeFunc       []interface{}                // invoke func
+	providers        []provides.Provider          // all provides
+	stopCh           chan struct{}                // stop chan,if you call Stop() application will exit
+	stopping         sync.WaitGroup               // Start waits the shutdown by Stop
+	dryRun           bool                         // validate engine only and exit
+	providesLoaded   bool                         // providers have been registered
+	confSections     []confSection                // config sections loaded before providers
+	health           *health.Registry             // health checks registry
+	healthAddr       string                       // health http server address
+	builtins         []*httpComponent             // built-in servers managed by engine
+	adminAddr        string                       // admin http server address
+	state            int32                        // engine lifecycle state
+	withLogger       bool                         // create the default logger even if no logger section
+	loggerOptions    []logger.Option              // default logger options,override the logger section
 
 	// config provider these are optional parameters
 	configDir       string                  // config dirname
@@ -46,6 +79,9 @@ type Engine struct {
 	configProvider  provides.ConfigProvider // all provides.ConfigProvider
 }
 
+// LoggerSection config section of default logger
+var LoggerSection = "logger"
+
 // engine default engine
 var engine *Engine
 
@@ -70,14 +106,23 @@ func IsSet(key string) bool {
 	return engine.IsSet(key)
 }
 
+// ReloadConf reload config file of default engine
+func ReloadConf() error {
+	return engine.ReloadConf()
+}
+
 // New create an application for msa engine
 func New(opts ...Option) *Engine {
 	e := &Engine{
 		gracefulWait:     5 * time.Second,
 		signal:           make(chan os.Signal, 1),
 		interruptSignals: InterruptSignals,
+		rotateSignals:    RotateSignals,
 		stopCh:           make(chan struct{}, 1),
 		injector:         defaultInjector(),
+		injectType:       factory.FbInject,
+		dryRun:           hasDryRunFlag(),
+		health:           health.New(),
 	}
 
 	for _, o := range opts {
@@ -92,26 +137,66 @@ func New(opts ...Option) *Engine {
 	// if the configuration file directory and file, regenerate a config interface.
 	e.resetConfInterface()
 
+	// create the default logger from logger config section and options
+	e.initLogger()
+
 	return e
 }
 
 // Start run app
 func (e *Engine) Start() {
+	// only validate config and inject wiring
+	if e.dryRun {
+		e.runDryRun()
+	}
+
+	// flush and close the default logger when startup panics,
+	// after startup it is closed at the end of shutdown
+	started := false
+	defer func() {
+		if !started {
+			e.closeLogger()
+		}
+	}()
+
+	e.setState(StateStarting)
+
+	// load config sections
+	if errs := e.loadConfSections(); len(errs) > 0 {
+		panic((&ValidateError{Errors: errs}).Error())
+	}
+
 	// load all provides
 	e.loadProvides()
 
 	// invoke inject objects
 	e.invokeInjects()
 
+	// register inject objects which implement health.Checker
+	e.registerHealthCheckers()
+
+	// start built-in servers before components,so the readiness is reported during startup
+	e.startBuiltins()
+
 	// run init and start action
 	e.run()
 
+	// the application is ready to receive traffic
+	e.setState(StateRunning)
+	e.health.SetReady(true)
+	started = true
+
 	// wait exit signal
 	e.waitExitSignal()
 }
 
-// Stop if receive active exit signal,the application will exit
+// Stop if receive active exit signal,the application will exit,
+// Start returns after the shutdown is finished.
 func (e *Engine) Stop() {
+	e.stopping.Add(1)
+	defer e.stopping.Done()
+
+	slog.Info("msa receive stop signal")
 	close(e.stopCh)
 	e.shutdown()
 }
@@ -121,15 +206,93 @@ func (e *Engine) LoadConf(key string, obj interface{}) error {
 	return e.configInterface.GetValue(key, obj)
 }
 
+// ReloadConf reload config file,the new values are returned by later LoadConf calls,
+// the objects which have been loaded are not changed.
+// The log levels of default logger are changed if the config has logger section.
+// The config interface must implement config.Reloader.
+func (e *Engine) ReloadConf() error {
+	err := errors.New("config interface does not support reload")
+	if r, ok := e.configInterface.(config.Reloader); ok {
+		err = r.Reload()
+	}
+
+	if err == nil {
+		err = e.reloadLogLevels()
+	}
+
+	if err != nil {
+		configReloads.With("failure").Inc()
+		return err
+	}
+
+	configReloads.With("success").Inc()
+	return nil
+}
+
+// initLogger create the default logger if the config has logger section or WithLogger is used,
+// and set it as the slog default so the engine messages are written by it
+func (e *Engine) initLogger() {
+	if !e.IsSet(LoggerSection) {
+		if e.withLogger {
+			logger.Default(e.loggerOptions...)
+			logger.SetSlogDefault(logger.DefaultLogger())
+		}
+
+		return
+	}
+
+	var conf logger.Config
+	if err := e.LoadConf(LoggerSection, &conf); err != nil {
+		panic("load logger config error: " + err.Error())
+	}
+
+	opts, err := conf.Options()
+	if err != nil {
+		panic(err.Error())
+	}
+
+	logger.Default(append(opts, e.loggerOptions...)...)
+	logger.SetSlogDefault(logger.DefaultLogger())
+}
+
+// reloadLogLevels apply level and modules of logger section to default logger
+func (e *Engine) reloadLogLevels() error {
+	if !e.IsSet(LoggerSection) {
+		return nil
+	}
+
+	var conf logger.LevelConfig
+	if err := e.LoadConf(LoggerSection, &conf); err != nil {
+		return err
+	}
+
+	return logger.ApplyLevelConfig(conf)
+}
+
+// Health return health checks registry
+func (e *Engine) Health() *health.Registry {
+	return e.health
+}
+
 // IsSet configInterface is set key
 func (e *Engine) IsSet(key string) bool {
 	return e.configInterface.IsSet(key)
 }
 
 func (e *Engine) run() {
+	for _, val := range e.injectValues {
+		if s, ok := val.Value.(engineSetter); ok {
+			s.SetEngine(e)
+		}
+	}
+
 	for _, val := range e.injectValues {
 		if initStream, ok := val.Value.(initializer); ok {
-			if err := initStream.Init(); err != nil {
+			var err error
+			observeComponent(val, "init", func() {
+				err = initStream.Init()
+			})
+			if err != nil {
 				panic("init error: " + err.Error())
 			}
 		}
@@ -137,21 +300,32 @@ func (e *Engine) run() {
 
 	for _, val := range e.injectValues {
 		if startStream, ok := val.Value.(starter); ok {
-			if err := startStream.Start(); err != nil {
+			var err error
+			observeComponent(val, "start", func() {
+				err = startStream.Start()
+			})
+			if err != nil {
 				panic("start error: " + err.Error())
 			}
 		}
 	}
 
-	log.Println("msa started successfully")
+	slog.Info("msa started successfully")
 }
 
 // loadProvides load providers and config inject providers
 func (e *Engine) loadProvides() {
+	// Validate and Start share the loaded providers,a failed load is retried
+	if e.providesLoaded {
+		return
+	}
+
 	for _, p := range e.providers {
 		provides.Register(p)
 	}
 
+	// the objects after this index are registered by configProvider
+	configIndex := len(provides.ProvideObjects())
 	if e.configProvider != nil {
 		// register all providers from configProvider
 		configProviders := e.configProvider.Provide(e.configInterface)
@@ -161,50 +335,159 @@ func (e *Engine) loadProvides() {
 	}
 
 	if provideObjects := provides.ProvideObjects(); len(provideObjects) > 0 {
-		e.injectValues = append(e.injectValues, provideObjects...)
+		e.addInjectValues(SourceProvider, provideObjects[:configIndex]...)
+		e.addInjectValues(SourceConfigProvider, provideObjects[configIndex:]...)
 	}
+
+	e.providesLoaded = true
 }
 
 func (e *Engine) waitExitSignal() {
+	// rotate the default logger files until exit
+	rotate := make(chan os.Signal, 1)
+	if len(e.rotateSignals) > 0 {
+		signal.Notify(rotate, e.rotateSignals...)
+		defer signal.Stop(rotate)
+	}
+
 	// We'll accept graceful shutdowns when quit via SIGINT (Ctrl+C)
 	// receive signal to exit main goroutine
 	// Block until we receive our signal.
 	signal.Notify(e.signal, e.interruptSignals...)
-	select {
-	case sig := <-e.signal:
-		signal.Stop(e.signal)
-		log.Println("receive exit signal: ", sig.String())
-		e.shutdown()
-	case <-e.stopCh:
-		log.Println("receive stop signal")
+	for {
+		select {
+		case sig := <-rotate:
+			if err := logger.Rotate(); err != nil {
+				slog.Error("msa rotate logger error", "error", err)
+			} else {
+				slog.Info("msa rotate logger", "signal", sig.String())
+			}
+		case sig := <-e.signal:
+			signal.Stop(e.signal)
+			slog.Info("msa receive exit signal", "signal", sig.String())
+			e.shutdown()
+			return
+		case <-e.stopCh:
+			e.stopping.Wait()
+			return
+		}
 	}
 }
 
 func (e *Engine) invokeInjects() {
 	// init inject objects
 	if err := e.injector.Provide(e.injectValues...); err != nil {
+		e.logMissingDeps()
 		panic("provide inject objects error: " + err.Error())
 	}
 
-	// invoke objects
-	if err := e.injector.Invoke(e.invokeFunc...); err != nil {
+	// populate objects and then call invoke funcs
+	if err := e.injector.Invoke(e.invokeFuncs); err != nil {
+		e.logMissingDeps()
 		panic("inject invoke error: " + err.Error())
 	}
 }
 
+// logMissingDeps log the dependencies which are not registered
+func (e *Engine) logMissingDeps() {
+	for _, dep := range e.Graph().Missing {
+		slog.Error("msa inject dependency not registered", "from", dep.From, "field", dep.Field, "to", dep.To)
+	}
+}
+
 // shutdown graceful stop application
 func (e *Engine) shutdown() {
-	defer log.Println("msa exit successfully")
+	// flip readiness to failing and wait load balancers to remove this instance
+	e.setState(StateDraining)
+	e.health.SetReady(false)
+	if e.drainDelay > 0 {
+		slog.Info("msa wait readiness propagation", "drain_delay", e.drainDelay.String())
+		time.Sleep(e.drainDelay)
+	}
+
+	e.drain()
 
+	e.setState(StateStopping)
 	for _, val := range e.injectValues {
 		if s, ok := val.Value.(stoppable); ok {
-			s.Stop()
+			observeComponent(val, "stop", s.Stop)
 		}
 	}
 
+	for _, b := range e.builtins {
+		b.Stop()
+	}
+
+	e.setState(StateStopped)
 	ctx, cancel := context.WithTimeout(context.Background(), e.gracefulWait)
 	defer cancel()
 	<-ctx.Done()
+	slog.Info("msa exit successfully")
+
+	// flush and close the default logger after the last message
+	e.closeLogger()
+}
+
+// closeLogger flush and close the default logger
+func (e *Engine) closeLogger() {
+	if err := logger.Close(); err != nil {
+		// slog and the default std logger write to the closed logger,so write to stderr directly
+		log.New(os.Stderr, "", log.LstdFlags).Println("msa close logger error: ", err)
+	}
+}
+
+// drain call Drain on all drainers concurrently within the graceful wait time
+func (e *Engine) drain() {
+	ctx, cancel := context.WithTimeout(context.Background(), e.gracefulWait)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, val := range e.injectValues {
+		d, ok := val.Value.(Drainer)
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		go func(d Drainer) {
+			defer wg.Done()
+			if err := d.Drain(ctx); err != nil {
+				slog.Error("msa drain error", "component", fmt.Sprintf("%T", d), "error", err)
+			}
+		}(d)
+	}
+
+	wg.Wait()
+}
+
+// registerHealthCheckers register inject objects which implement health.Checker,
+// the check name is the inject name or the object type.
+func (e *Engine) registerHealthCheckers() {
+	for _, obj := range e.injectValues {
+		checker, ok := obj.Value.(health.Checker)
+		if !ok {
+			continue
+		}
+
+		e.health.Register(objectName(obj), checker)
+	}
+}
+
+// startBuiltins start built-in servers
+func (e *Engine) startBuiltins() {
+	if e.healthAddr != "" {
+		e.builtins = append(e.builtins, newHTTPComponent("health", e.healthAddr, e.health.Handler(), e.gracefulWait))
+	}
+
+	if e.adminAddr != "" {
+		e.builtins = append(e.builtins, newHTTPComponent("admin", e.adminAddr, e.adminHandler(), e.gracefulWait))
+	}
+
+	for _, b := range e.builtins {
+		if err := b.Start(); err != nil {
+			panic("start " + b.name + " server error: " + err.Error())
+		}
+	}
 }
 
 func (e *Engine) resetConfInterface() {
@@ -224,6 +507,15 @@ func defaultInjector() gdi.Injector {
 	return factory.CreateDI(factory.FbInject)
 }
 
+// newInjector create a new injector of the engine inject type
+func (e *Engine) newInjector() gdi.Injector {
+	if e.injectType == 0 {
+		return defaultInjector()
+	}
+
+	return factory.CreateDI(e.injectType)
+}
+
 func defaultConfig() config.ConfigInterface {
 	return config.New()
 }
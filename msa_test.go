@@ -0,0 +1,160 @@
+package msa
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-god/gdi"
+
+	"github.com/go-god/msa/config"
+	"github.com/go-god/msa/health"
+	"github.com/go-god/msa/logger"
+)
+
+type fakeDrainer struct {
+	e       *Engine
+	start   time.Time
+	events  []string
+	ready   bool
+	state   State
+	elapsed time.Duration
+}
+
+func (d *fakeDrainer) Drain(ctx context.Context) error {
+	d.events = append(d.events, "drain")
+	d.ready = d.e.health.Readiness(ctx).Status == health.StatusUp
+	d.state = d.e.State()
+	d.elapsed = time.Since(d.start)
+	return nil
+}
+
+func (d *fakeDrainer) Stop() {
+	d.events = append(d.events, "stop")
+}
+
+// TestEngineDrain test shutdown flips readiness,waits the drain delay and drains before stop.
+func TestEngineDrain(t *testing.T) {
+	e := &Engine{
+		gracefulWait: 10 * time.Millisecond,
+		drainDelay:   50 * time.Millisecond,
+		health:       health.New(),
+	}
+	d := &fakeDrainer{e: e}
+	WithInjectValues(&gdi.Object{Value: d})(e)
+	e.health.SetReady(true)
+
+	d.start = time.Now()
+	e.shutdown()
+
+	if d.ready || d.state != StateDraining {
+		t.Fatalf("drain with ready: %v state: %s", d.ready, d.state)
+	}
+
+	if d.elapsed < e.drainDelay {
+		t.Fatalf("drain after %s,drain delay: %s", d.elapsed, e.drainDelay)
+	}
+
+	if len(d.events) != 2 || d.events[0] != "drain" || d.events[1] != "stop" {
+		t.Fatalf("events: %v", d.events)
+	}
+
+	if e.State() != StateStopped {
+		t.Fatalf("state: %s", e.State())
+	}
+}
+
+type panicInit struct{}
+
+func (*panicInit) Init() error {
+	ctx := context.Background()
+	logger.Info(ctx, "init")
+	logger.Info(ctx, "init")
+	return errors.New("init failed")
+}
+
+// TestEngineStartPanicClosesLogger test the default logger is closed when startup panics.
+func TestEngineStartPanicClosesLogger(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.yaml"), []byte("app: test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// the drop summary is only written by Close within an hour
+	e := New(WithConfigInterface(config.New(config.WithConfigDir(dir), config.WithConfigFile("app.yaml"))),
+		WithLogger(logger.WithLogDir(dir), logger.WithLogFilename("panic.log"), logger.WithStdout(false),
+			logger.WithSampling(time.Minute, 1, 0), logger.WithDropSummary(time.Hour)),
+		WithInjectValues(&gdi.Object{Value: &panicInit{}}))
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatal("start does not panic")
+			}
+		}()
+
+		e.Start()
+	}()
+
+	b, err := os.ReadFile(filepath.Join(dir, "panic.log"))
+	if err != nil || !strings.Contains(string(b), "log lines dropped") {
+		t.Fatalf("log file: %s error: %v", b, err)
+	}
+}
+
+// TestEngineStopClosesLogger test Start returns after Stop finishes the shutdown
+// and the default logger is closed after the last engine message.
+func TestEngineStopClosesLogger(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.yaml"), []byte("app: test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := New(WithConfigInterface(config.New(config.WithConfigDir(dir), config.WithConfigFile("app.yaml"))),
+		WithLogger(logger.WithLogDir(dir), logger.WithLogFilename("stop.log"), logger.WithStdout(false),
+			logger.WithSampling(time.Minute, 1, 0), logger.WithDropSummary(time.Hour)),
+		WithGracefulWait(10*time.Millisecond))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		e.Start()
+	}()
+
+	for e.State() != StateRunning {
+		time.Sleep(time.Millisecond)
+	}
+
+	logger.Info(context.Background(), "running")
+	logger.Info(context.Background(), "running")
+	go e.Stop()
+	<-done
+
+	b, err := os.ReadFile(filepath.Join(dir, "stop.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exit, summary := strings.Index(string(b), "msa exit successfully"), strings.Index(string(b), "log lines dropped")
+	if exit < 0 || summary < exit {
+		t.Fatalf("log file: %s", b)
+	}
+}
+
+// TestObserveComponent test every component action is observed by the duration histogram.
+func TestObserveComponent(t *testing.T) {
+	obj := &gdi.Object{Value: &graphRepo{}, Name: "observe"}
+	h := componentDuration.With("observe", "start")
+	count := h.Count()
+	for i := 0; i < 2; i++ {
+		observeComponent(obj, "start", func() {})
+	}
+
+	if h.Count() != count+2 {
+		t.Fatalf("component duration count: %d want: %d", h.Count(), count+2)
+	}
+}
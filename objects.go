@@ -0,0 +1,71 @@
+package msa
+
+import (
+	"fmt"
+
+	"github.com/go-god/gdi"
+)
+
+// ObjectSource where an inject object comes from
+type ObjectSource string
+
+const (
+	// SourceOption the object is set by msa.WithInjectValues
+	SourceOption ObjectSource = "option"
+	// SourceProvider the object is provided by provides.Provider
+	SourceProvider ObjectSource = "provider"
+	// SourceConfigProvider the object is provided by provides.ConfigProvider
+	SourceConfigProvider ObjectSource = "config_provider"
+)
+
+// ObjectInfo registered inject object info
+type ObjectInfo struct {
+	Type   string       `json:"type"`
+	Name   string       `json:"name,omitempty"`
+	Group  string       `json:"group,omitempty"`
+	Source ObjectSource `json:"source"`
+	Value  interface{}  `json:"-"`
+}
+
+// Objects return all registered inject objects in register order.
+// The objects of providers are only included after they have been loaded by Start.
+func (e *Engine) Objects() []ObjectInfo {
+	objects := make([]ObjectInfo, 0, len(e.injectValues))
+	for _, obj := range e.injectValues {
+		if obj == nil {
+			continue
+		}
+
+		objects = append(objects, ObjectInfo{
+			Type:   fmt.Sprintf("%T", obj.Value),
+			Name:   obj.Name,
+			Group:  obj.Group,
+			Source: e.objectSource(obj),
+			Value:  obj.Value,
+		})
+	}
+
+	return objects
+}
+
+// addInjectValues add inject objects and record their source
+func (e *Engine) addInjectValues(source ObjectSource, objects ...*gdi.Object) {
+	if e.objectSources == nil {
+		e.objectSources = make(map[*gdi.Object]ObjectSource, len(objects))
+	}
+
+	for _, obj := range objects {
+		e.objectSources[obj] = source
+	}
+
+	e.injectValues = append(e.injectValues, objects...)
+}
+
+// objectSource return the source of inject object
+func (e *Engine) objectSource(obj *gdi.Object) ObjectSource {
+	if source, ok := e.objectSources[obj]; ok {
+		return source
+	}
+
+	return SourceOption
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/go-god/gdi/factory"
 
 	"github.com/go-god/msa/config"
+	"github.com/go-god/msa/health"
 	"github.com/go-god/msa/logger"
 	"github.com/go-god/msa/provides"
 )
@@ -22,11 +23,20 @@ func WithGracefulWait(t time.Duration) Option {
 	}
 }
 
+// WithDrainDelay set the delay between readiness failing and draining on shutdown,
+// it should be longer than the readiness probe period of load balancers.
+func WithDrainDelay(t time.Duration) Option {
+	return func(e *Engine) {
+		e.drainDelay = t
+	}
+}
+
 // WithInjector set injector
 // inject type as: factory.FbInject or factory.DigInject
 func WithInjector(injectType factory.InjectType) Option {
 	return func(e *Engine) {
 		e.injector = factory.CreateDI(injectType)
+		e.injectType = injectType
 	}
 }
 
@@ -37,6 +47,18 @@ func WithInjectValues(objects ...*gdi.Object) Option {
 	}
 }
 
+// WithInvoke add invoke funcs,they will be called in order after inject objects are populated.
+// The parameters of fn can be *msa.Engine, config.ConfigInterface, logger.Logger,
+// context.Context or any inject object type.
+// The logger.Logger parameter is logger.DefaultLogger(),if neither the logger config section
+// nor WithLogger is used,it is created with the logger.Default options and writes to ./logs.
+// If fn returns an error,the application startup will be aborted.
+func WithInvoke(fns ...interface{}) Option {
+	return func(e *Engine) {
+		e.invokeFunc = append(e.invokeFunc, fns...)
+	}
+}
+
 // WithInterruptSignals set engine interruptSignals
 func WithInterruptSignals(signals ...os.Signal) Option {
 	return func(e *Engine) {
@@ -44,6 +66,13 @@ func WithInterruptSignals(signals ...os.Signal) Option {
 	}
 }
 
+// WithRotateSignals set signals to rotate the default logger files,no signals disable it
+func WithRotateSignals(signals ...os.Signal) Option {
+	return func(e *Engine) {
+		e.rotateSignals = signals
+	}
+}
+
 // WithConfigInterface set config read interface
 func WithConfigInterface(c config.ConfigInterface) Option {
 	return func(e *Engine) {
@@ -65,9 +94,49 @@ func WithConfigProvider(configProvider provides.ConfigProvider) Option {
 	}
 }
 
-// WithLogger logger config
+// WithConfSection load config section key to obj before providers are loaded,
+// obj must be a pointer. If obj implements Validate() error, it will be validated.
+func WithConfSection(key string, obj interface{}) Option {
+	return func(e *Engine) {
+		e.confSections = append(e.confSections, confSection{key: key, obj: obj})
+	}
+}
+
+// WithDryRun only validate config and inject wiring without Init or Start,
+// then exit the process. It is also enabled by the --dry-run command line flag.
+func WithDryRun(b bool) Option {
+	return func(e *Engine) {
+		e.dryRun = b
+	}
+}
+
+// WithHealthServer serve health.LivenessPath and health.ReadinessPath on addr,eg: ":8081"
+func WithHealthServer(addr string) Option {
+	return func(e *Engine) {
+		e.healthAddr = addr
+	}
+}
+
+// WithAdminServer serve admin endpoints on addr,eg: "127.0.0.1:9090"
+// it exposes health, engine state, masked config, inject objects, build info,
+// pprof and runtime log level changes. The addr should not be exposed to the public.
+func WithAdminServer(addr string) Option {
+	return func(e *Engine) {
+		e.adminAddr = addr
+	}
+}
+
+// WithHealthChecker register a health checker with options
+func WithHealthChecker(name string, checker health.Checker, opts ...health.Option) Option {
+	return func(e *Engine) {
+		e.health.Register(name, checker, opts...)
+	}
+}
+
+// WithLogger create the default logger,opts override the logger config section
 func WithLogger(opts ...logger.Option) Option {
 	return func(e *Engine) {
-		logger.Default(opts...)
+		e.withLogger = true
+		e.loggerOptions = append(e.loggerOptions, opts...)
 	}
 }
@@ -0,0 +1,168 @@
+package provides
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/go-god/gdi"
+
+	"github.com/go-god/msa/config"
+)
+
+// DefaultComponentsSection default components section in config file
+var DefaultComponentsSection = "components"
+
+// ComponentFactory create a component from config.
+// key is the config key of the component section,eg: components.db
+// the factory can read its own settings by c.GetValue(key, &conf)
+type ComponentFactory func(c config.ConfigInterface, key string) (interface{}, error)
+
+// componentConf common fields of a component section
+/**
+components:
+  db:                 # component name,it will be used as inject name
+    type: mysql       # component type,registered by RegisterFactory
+    group: storage    # inject group,optional
+    disabled: false   # skip this component,optional
+    dsn: "..."        # other fields are read by the factory
+*/
+type componentConf struct {
+	Type     string `json:"type" mapstructure:"type"`
+	Group    string `json:"group" mapstructure:"group"`
+	Disabled bool   `json:"disabled" mapstructure:"disabled"`
+}
+
+var (
+	factoryLock sync.RWMutex
+	factories   = make(map[string]ComponentFactory, 10)
+)
+
+// RegisterFactory register component factory by type name,eg: mysql,redis,http_server
+func RegisterFactory(typ string, f ComponentFactory) {
+	if typ == "" {
+		panic("component factory type is empty")
+	}
+
+	if f == nil {
+		panic("component factory is nil")
+	}
+
+	factoryLock.Lock()
+	defer factoryLock.Unlock()
+	if _, ok := factories[typ]; ok {
+		panic("component factory " + typ + " already registered")
+	}
+
+	factories[typ] = f
+}
+
+// Factory return component factory by type name
+func Factory(typ string) (ComponentFactory, bool) {
+	factoryLock.RLock()
+	defer factoryLock.RUnlock()
+	f, ok := factories[typ]
+	return f, ok
+}
+
+// FactoryTypes return all registered component types
+func FactoryTypes() []string {
+	factoryLock.RLock()
+	defer factoryLock.RUnlock()
+	types := make([]string, 0, len(factories))
+	for typ := range factories {
+		types = append(types, typ)
+	}
+
+	sort.Strings(types)
+	return types
+}
+
+// componentProvider provider for a component built by factory
+type componentProvider struct {
+	obj *gdi.Object
+}
+
+// Provide return inject object
+func (p *componentProvider) Provide() *gdi.Object {
+	return p.obj
+}
+
+// componentConfigProvider config provider for components section
+type componentConfigProvider struct {
+	section string
+}
+
+// NewComponentProvider create a ConfigProvider which walks the components section
+// and builds each component by its registered factory.
+// If section is empty,DefaultComponentsSection will be used.
+func NewComponentProvider(section string) ConfigProvider {
+	if section == "" {
+		section = DefaultComponentsSection
+	}
+
+	return &componentConfigProvider{section: section}
+}
+
+// Provide build all providers from components section,it will panic if a component is invalid
+func (p *componentConfigProvider) Provide(c config.ConfigInterface) []Provider {
+	providers, err := BuildComponents(c, p.section)
+	if err != nil {
+		panic("provide components error: " + err.Error())
+	}
+
+	return providers
+}
+
+// BuildComponents build all components from the section of config
+// components are sorted by name, the name is used as inject name.
+func BuildComponents(c config.ConfigInterface, section string) ([]Provider, error) {
+	if !c.IsSet(section) {
+		return nil, nil
+	}
+
+	components := make(map[string]componentConf, 10)
+	if err := c.GetValue(section, &components); err != nil {
+		return nil, fmt.Errorf("read section %s error: %w", section, err)
+	}
+
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	providers := make([]Provider, 0, len(names))
+	for _, name := range names {
+		conf := components[name]
+		if conf.Disabled {
+			continue
+		}
+
+		if conf.Type == "" {
+			return nil, errors.New("component " + name + " has no type")
+		}
+
+		f, ok := Factory(conf.Type)
+		if !ok {
+			return nil, fmt.Errorf("component %s type %s not registered", name, conf.Type)
+		}
+
+		value, err := f(c, section+"."+name)
+		if err != nil {
+			return nil, fmt.Errorf("create component %s error: %w", name, err)
+		}
+
+		if value == nil {
+			return nil, fmt.Errorf("component %s factory returned nil", name)
+		}
+
+		providers = append(providers, &componentProvider{
+			obj: &gdi.Object{Value: value, Name: name, Group: conf.Group},
+		})
+	}
+
+	return providers, nil
+}
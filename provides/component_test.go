@@ -0,0 +1,54 @@
+package provides
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-god/msa/config"
+)
+
+type testRedis struct {
+	Addr string `mapstructure:"addr"`
+}
+
+const testComponentsConf = `
+components:
+  cache:
+    type: test_redis
+    group: storage
+    addr: 127.0.0.1:6379
+  cache_bak:
+    type: test_redis
+    disabled: true
+`
+
+// TestBuildComponents test build components from config.
+func TestBuildComponents(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "app.yaml"), []byte(testComponentsConf), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	RegisterFactory("test_redis", func(c config.ConfigInterface, key string) (interface{}, error) {
+		r := &testRedis{}
+		err := c.GetValue(key, r)
+		return r, err
+	})
+
+	c := config.New(config.WithConfigDir(dir), config.WithConfigFile("app.yaml"))
+	providers := NewComponentProvider("").Provide(c)
+	if len(providers) != 1 {
+		t.Fatalf("providers length: %d", len(providers))
+	}
+
+	obj := providers[0].Provide()
+	if obj.Name != "cache" || obj.Group != "storage" {
+		t.Fatalf("component name: %s group: %s", obj.Name, obj.Group)
+	}
+
+	if r := obj.Value.(*testRedis); r.Addr != "127.0.0.1:6379" {
+		t.Fatalf("component addr: %s", r.Addr)
+	}
+}
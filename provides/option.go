@@ -1,7 +1,7 @@
 package provides
 
 // Option providerOption functional option
-type Option func(o providerOption)
+type Option func(o *providerOption)
 type providerOption struct {
 	name  string // provider name
 	group string // provider group
@@ -9,14 +9,14 @@ type providerOption struct {
 
 // WithProviderName set provider name
 func WithProviderName(name string) Option {
-	return func(o providerOption) {
+	return func(o *providerOption) {
 		o.name = name
 	}
 }
 
 // WithProviderGroup set provider group
 func WithProviderGroup(group string) Option {
-	return func(o providerOption) {
+	return func(o *providerOption) {
 		o.group = group
 	}
 }
@@ -21,7 +21,7 @@ var provideObjects = make([]*gdi.Object, 0, 20)
 // Register register Provider
 func Register(p Provider, opts ...Option) {
 	obj := p.Provide()
-	providerOpt := providerOption{}
+	providerOpt := &providerOption{}
 	for _, o := range opts {
 		o(providerOpt)
 	}
@@ -0,0 +1,121 @@
+package msa
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	// ErrObjectNotFound inject object not found
+	ErrObjectNotFound = errors.New("inject object not found")
+
+	// ErrNoEngine engine is nil and the default engine has not been created by msa.Start
+	ErrNoEngine = errors.New("no engine to resolve from")
+)
+
+// Resolve find the inject object by name and store it in the value pointed to by ptr.
+// If name is empty,the first object which is assignable to the type of *ptr will be used.
+// eg:
+// var db *sql.DB
+// err := engine.Resolve("db", &db)
+func (e *Engine) Resolve(name string, ptr interface{}) error {
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("resolve ptr must be a non-nil pointer,got %T", ptr)
+	}
+
+	v, ok := e.findObject(name, rv.Elem().Type())
+	if !ok {
+		return fmt.Errorf("resolve %s name %q: %w", rv.Elem().Type(), name, ErrObjectNotFound)
+	}
+
+	rv.Elem().Set(v)
+	return nil
+}
+
+// ResolveGroup find all inject objects of the group which are assignable to
+// the element type of *slicePtr, and append them to the slice in register order.
+// If group is empty,all objects of the element type will be appended.
+// eg:
+// var checkers []health.Checker
+// err := engine.ResolveGroup("", &checkers)
+func (e *Engine) ResolveGroup(group string, slicePtr interface{}) error {
+	rv := reflect.ValueOf(slicePtr)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("resolve group slicePtr must be a non-nil pointer to slice,got %T", slicePtr)
+	}
+
+	slice := rv.Elem()
+	elemType := slice.Type().Elem()
+	for _, obj := range e.injectValues {
+		if obj == nil || obj.Value == nil || (group != "" && obj.Group != group) {
+			continue
+		}
+
+		if v := reflect.ValueOf(obj.Value); v.Type().AssignableTo(elemType) {
+			slice = reflect.Append(slice, v)
+		}
+	}
+
+	rv.Elem().Set(slice)
+	return nil
+}
+
+// findObject find inject object by name and type,name is optional
+func (e *Engine) findObject(name string, t reflect.Type) (reflect.Value, bool) {
+	for _, obj := range e.injectValues {
+		if obj == nil || obj.Value == nil || (name != "" && obj.Name != name) {
+			continue
+		}
+
+		if v := reflect.ValueOf(obj.Value); v.Type().AssignableTo(t) {
+			return v, true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// Resolve find the inject object of type T by name,e is nil means default engine.
+// If e is nil and the default engine is not created,ErrNoEngine is returned.
+// eg:
+// db, err := msa.Resolve[*sql.DB](nil, "db")
+func Resolve[T any](e *Engine, name string) (T, error) {
+	var v T
+	e, err := engineOrDefault(e)
+	if err != nil {
+		return v, err
+	}
+
+	err = e.Resolve(name, &v)
+	return v, err
+}
+
+// ResolveGroup find all inject objects of type T in the group,e is nil means default engine.
+// If e is nil and the default engine is not created,ErrNoEngine is returned.
+// eg:
+// handlers, err := msa.ResolveGroup[http.Handler](nil, "http_handler")
+func ResolveGroup[T any](e *Engine, group string) ([]T, error) {
+	e, err := engineOrDefault(e)
+	if err != nil {
+		return nil, err
+	}
+
+	var vs []T
+	err = e.ResolveGroup(group, &vs)
+	return vs, err
+}
+
+// engineOrDefault return e or the default engine if e is nil
+func engineOrDefault(e *Engine) (*Engine, error) {
+	if e == nil {
+		e = engine
+	}
+
+	if e == nil {
+		return nil, ErrNoEngine
+	}
+
+	return e, nil
+}
@@ -0,0 +1,58 @@
+package msa
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-god/gdi"
+)
+
+// TestEngineResolve test resolve objects by name and group.
+func TestEngineResolve(t *testing.T) {
+	e := &Engine{}
+	WithInjectValues(
+		&gdi.Object{Value: &graphService{}, Group: "svc"},
+		&gdi.Object{Value: &graphRepo{}, Name: "repo", Group: "svc"},
+		&gdi.Object{Value: &graphRepo{}, Name: "repo2"},
+	)(e)
+
+	var repo *graphRepo
+	if err := e.Resolve("repo2", &repo); err != nil || repo != e.injectValues[2].Value {
+		t.Fatalf("resolve repo2 error: %v", err)
+	}
+
+	var cache *graphCache
+	if err := e.Resolve("", &cache); !errors.Is(err, ErrObjectNotFound) {
+		t.Fatalf("resolve cache error: %v", err)
+	}
+
+	var starters []starter
+	if err := e.ResolveGroup("svc", &starters); err != nil || len(starters) != 1 {
+		t.Fatalf("resolve group starters: %v error: %v", starters, err)
+	}
+
+	var all []interface{}
+	if err := e.ResolveGroup("", &all); err != nil || len(all) != 3 {
+		t.Fatalf("resolve all: %v error: %v", all, err)
+	}
+
+	if repo2, err := Resolve[*graphRepo](e, "repo2"); err != nil || repo2 != repo {
+		t.Fatalf("generic resolve repo2: %v error: %v", repo2, err)
+	}
+
+	if _, err := Resolve[*graphCache](e, ""); !errors.Is(err, ErrObjectNotFound) {
+		t.Fatalf("generic resolve cache error: %v", err)
+	}
+
+	if svc, err := ResolveGroup[starter](e, "svc"); err != nil || len(svc) != 1 {
+		t.Fatalf("generic resolve group svc: %v error: %v", svc, err)
+	}
+
+	if _, err := Resolve[*graphRepo](nil, "repo"); !errors.Is(err, ErrNoEngine) {
+		t.Fatalf("resolve without engine error: %v", err)
+	}
+
+	if _, err := ResolveGroup[starter](nil, "svc"); !errors.Is(err, ErrNoEngine) {
+		t.Fatalf("resolve group without engine error: %v", err)
+	}
+}
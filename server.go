@@ -0,0 +1,56 @@
+package msa
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+// httpComponent http server managed by engine,it implements starter and stoppable
+type httpComponent struct {
+	name        string
+	server      *http.Server
+	stopTimeout time.Duration
+}
+
+// newHTTPComponent create http server component
+func newHTTPComponent(name string, addr string, handler http.Handler, stopTimeout time.Duration) *httpComponent {
+	return &httpComponent{
+		name: name,
+		server: &http.Server{
+			Addr:              addr,
+			Handler:           handler,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		stopTimeout: stopTimeout,
+	}
+}
+
+// Start listen addr and serve in background
+func (h *httpComponent) Start() error {
+	ln, err := net.Listen("tcp", h.server.Addr)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("msa server listening", "server", h.name, "addr", ln.Addr().String())
+	go func() {
+		if err := h.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("msa server error", "server", h.name, "error", err)
+		}
+	}()
+
+	return nil
+}
+
+// Stop shutdown server
+func (h *httpComponent) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), h.stopTimeout)
+	defer cancel()
+	if err := h.server.Shutdown(ctx); err != nil {
+		slog.Error("msa server shutdown error", "server", h.name, "error", err)
+	}
+}
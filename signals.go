@@ -7,6 +7,12 @@ import (
 
 // InterruptSignals interrupt signals.
 var InterruptSignals = []os.Signal{
-	syscall.SIGINT, syscall.SIGTERM, os.Interrupt, syscall.SIGHUP,
+	syscall.SIGINT, syscall.SIGTERM, os.Interrupt,
 	syscall.SIGSTOP, syscall.SIGQUIT,
 }
+
+// RotateSignals the default logger files are rotated when receive these signals,
+// so external logrotate can move the files.
+var RotateSignals = []os.Signal{
+	syscall.SIGHUP, syscall.SIGUSR1,
+}
@@ -0,0 +1,52 @@
+package msa
+
+import (
+	"sync/atomic"
+)
+
+// State engine lifecycle state
+type State int32
+
+const (
+	// StateNew the engine is created
+	StateNew State = iota
+	// StateStarting the engine is loading providers and starting components
+	StateStarting
+	// StateRunning all components are started
+	StateRunning
+	// StateDraining the engine is draining before stop
+	StateDraining
+	// StateStopping the engine is stopping components
+	StateStopping
+	// StateStopped all components are stopped
+	StateStopped
+)
+
+var stateNames = map[State]string{
+	StateNew:      "new",
+	StateStarting: "starting",
+	StateRunning:  "running",
+	StateDraining: "draining",
+	StateStopping: "stopping",
+	StateStopped:  "stopped",
+}
+
+// String return state name
+func (s State) String() string {
+	if name, ok := stateNames[s]; ok {
+		return name
+	}
+
+	return "unknown"
+}
+
+// State return engine lifecycle state
+func (e *Engine) State() State {
+	return State(atomic.LoadInt32(&e.state))
+}
+
+// setState set engine lifecycle state
+func (e *Engine) setState(s State) {
+	atomic.StoreInt32(&e.state, int32(s))
+	observeState(s)
+}
@@ -0,0 +1,66 @@
+package tracing
+
+import (
+	"errors"
+)
+
+// exporter types
+const (
+	ExporterNone   = "none"
+	ExporterFile   = "file"
+	ExporterMemory = "memory"
+)
+
+// Config tracer provider config
+/**
+tracing:
+  service_name: demo
+  sample_ratio: 1      # 0 ~ 1
+  exporter: file       # none, file, memory
+  file: ./logs/spans.log
+*/
+type Config struct {
+	ServiceName string  `json:"service_name" mapstructure:"service_name"`
+	SampleRatio float64 `json:"sample_ratio" mapstructure:"sample_ratio"`
+	Exporter    string  `json:"exporter" mapstructure:"exporter"`
+	File        string  `json:"file" mapstructure:"file"`
+}
+
+// DefaultConfig return default tracer provider config
+func DefaultConfig() Config {
+	return Config{
+		SampleRatio: 1,
+		Exporter:    ExporterNone,
+	}
+}
+
+// Validate check config
+func (c *Config) Validate() error {
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return errors.New("tracing sample_ratio must be in [0,1]")
+	}
+
+	switch c.Exporter {
+	case "", ExporterNone, ExporterMemory:
+	case ExporterFile:
+		if c.File == "" {
+			return errors.New("tracing file exporter requires file")
+		}
+	default:
+		return errors.New("tracing exporter " + c.Exporter + " not supported")
+	}
+
+	return nil
+}
+
+// newExporter create exporter by config
+func (c *Config) newExporter() (Exporter, error) {
+	switch c.Exporter {
+	case ExporterFile:
+		return NewFileExporter(c.File)
+	case ExporterMemory:
+		return NewMemoryExporter(), nil
+	}
+
+	return nil, nil
+}
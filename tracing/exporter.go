@@ -0,0 +1,88 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Exporter export finished spans
+type Exporter interface {
+	ExportSpan(data *SpanData) error
+	Shutdown(ctx context.Context) error
+}
+
+// FileExporter write spans to file as json lines
+type FileExporter struct {
+	mu  sync.Mutex
+	f   *os.File
+	enc *json.Encoder
+}
+
+// NewFileExporter create file exporter,the file is appended
+func NewFileExporter(filename string) (*FileExporter, error) {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileExporter{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+// ExportSpan write span as a json line
+func (e *FileExporter) ExportSpan(data *SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.enc.Encode(data)
+}
+
+// Shutdown close file
+func (e *FileExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.f.Close()
+}
+
+// MemoryExporter keep spans in memory,it is a local collector stand-in for testing
+type MemoryExporter struct {
+	mu    sync.Mutex
+	spans []*SpanData
+}
+
+// NewMemoryExporter create memory exporter
+func NewMemoryExporter() *MemoryExporter {
+	return &MemoryExporter{}
+}
+
+// ExportSpan keep span
+func (e *MemoryExporter) ExportSpan(data *SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, data)
+	return nil
+}
+
+// Spans return exported spans
+func (e *MemoryExporter) Spans() []*SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]*SpanData(nil), e.spans...)
+}
+
+// Reset remove all spans
+func (e *MemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = nil
+}
+
+// Shutdown do nothing
+func (e *MemoryExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
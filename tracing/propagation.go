@@ -0,0 +1,71 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+)
+
+// remoteKey context key of remote span context
+type remoteKey struct{}
+
+// Inject set traceparent header from the span context of ctx
+func Inject(ctx context.Context, header http.Header) {
+	if sc := SpanContextFromContext(ctx); sc.IsValid() {
+		header.Set(TraceParentHeader, sc.TraceParent())
+	}
+}
+
+// Extract return context with the remote span context of traceparent header,
+// the next span started from it will be its child.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	sc, err := ParseTraceParent(header.Get(TraceParentHeader))
+	if err != nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// Middleware http middleware which extracts the traceparent header,
+// starts a server span for each request with the default provider and
+// echoes the traceparent header in the response.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := Extract(r.Context(), r.Header)
+		ctx, span := Start(ctx, r.Method+" "+r.URL.Path, WithSpanKind(KindServer))
+		defer span.End()
+
+		span.SetAttribute("http.method", r.Method)
+		span.SetAttribute("http.target", r.RequestURI)
+		w.Header().Set(TraceParentHeader, span.SpanContext().TraceParent())
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Transport http.RoundTripper which starts a client span and
+// injects the traceparent header to outgoing requests
+type Transport struct {
+	Base http.RoundTripper // if nil,http.DefaultTransport is used
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ctx, span := Start(req.Context(), req.Method+" "+req.URL.Host, WithSpanKind(KindClient))
+	defer span.End()
+
+	req = req.Clone(ctx)
+	Inject(ctx, req.Header)
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+
+	span.SetAttribute("http.status_code", resp.StatusCode)
+	return resp, nil
+}
@@ -0,0 +1,196 @@
+// Package tracing w3c trace context compatible tracing,
+// trace_id and span_id are added to log entries by the logger package.
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"log/slog"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-god/msa/config"
+	"github.com/go-god/msa/provides"
+)
+
+// FactoryType component type of tracer provider in components config section
+const FactoryType = "tracer"
+
+// span kinds
+const (
+	KindInternal = "internal"
+	KindServer   = "server"
+	KindClient   = "client"
+)
+
+// defaultProvider default tracer provider,it does not export spans until SetDefault
+var defaultProvider atomic.Value
+
+func init() {
+	defaultProvider.Store(NewProvider(Config{SampleRatio: 1}, nil))
+	provides.RegisterFactory(FactoryType, func(c config.ConfigInterface, key string) (interface{}, error) {
+		return NewFromConfig(c, key)
+	})
+}
+
+// Default return default tracer provider
+func Default() *Provider {
+	return defaultProvider.Load().(*Provider)
+}
+
+// SetDefault set default tracer provider
+func SetDefault(p *Provider) {
+	defaultProvider.Store(p)
+}
+
+// Start start a span with default provider
+func Start(ctx context.Context, name string, opts ...SpanOption) (context.Context, *Span) {
+	return Default().Start(ctx, name, opts...)
+}
+
+// Provider tracer provider component,it implements Init and Stop,
+// on Init it is set as the default provider.
+type Provider struct {
+	conf     Config
+	exporter Exporter
+	mu       sync.Mutex
+}
+
+// NewProvider create tracer provider,if exporter is nil spans are not exported
+func NewProvider(conf Config, exporter Exporter) *Provider {
+	return &Provider{conf: conf, exporter: exporter}
+}
+
+// NewFromConfig create tracer provider from config section key
+func NewFromConfig(c config.ConfigInterface, key string) (*Provider, error) {
+	conf := DefaultConfig()
+	if err := c.GetValue(key, &conf); err != nil {
+		return nil, err
+	}
+
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+
+	exporter, err := conf.newExporter()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewProvider(conf, exporter), nil
+}
+
+// Config return provider config
+func (p *Provider) Config() Config {
+	return p.conf
+}
+
+// Exporter return span exporter
+func (p *Provider) Exporter() Exporter {
+	return p.exporter
+}
+
+// Init set the provider as default provider
+func (p *Provider) Init() error {
+	SetDefault(p)
+	return nil
+}
+
+// Stop shutdown exporter
+func (p *Provider) Stop() {
+	if p.exporter == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := p.exporter.Shutdown(ctx); err != nil {
+		slog.Error("tracing exporter shutdown error", "error", err)
+	}
+}
+
+// Start start a span,it is a child of the span or the remote span context in ctx.
+// The returned context carries the span and the logger trace fields.
+func (p *Provider) Start(ctx context.Context, name string, opts ...SpanOption) (context.Context, *Span) {
+	o := &spanOption{kind: KindInternal}
+	for _, fn := range opts {
+		fn(o)
+	}
+
+	s := &Span{provider: p, name: name, kind: o.kind, start: time.Now(), attrs: o.attrs}
+	if parent := SpanContextFromContext(ctx); parent.IsValid() && !o.newRoot {
+		s.sc = SpanContext{TraceID: parent.TraceID, Sampled: parent.Sampled}
+		s.parent = parent.SpanID
+	} else {
+		s.sc = SpanContext{TraceID: newTraceID()}
+		s.sc.Sampled = p.sampled(s.sc.TraceID)
+	}
+
+	s.sc.SpanID = newSpanID()
+	return ContextWithSpan(ctx, s), s
+}
+
+// sampled decide whether to sample a new trace by trace id and sample ratio
+func (p *Provider) sampled(t TraceID) bool {
+	if p.conf.SampleRatio >= 1 {
+		return true
+	}
+
+	if p.conf.SampleRatio <= 0 {
+		return false
+	}
+
+	bound := uint64(p.conf.SampleRatio * math.MaxUint64)
+	return binary.BigEndian.Uint64(t[8:]) < bound
+}
+
+// export export span data
+func (p *Provider) export(data *SpanData) {
+	if p.exporter == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.exporter.ExportSpan(data); err != nil {
+		slog.Error("tracing export span error", "error", err)
+	}
+}
+
+// SpanOption span option
+type SpanOption func(o *spanOption)
+
+type spanOption struct {
+	kind    string
+	attrs   map[string]interface{}
+	newRoot bool
+}
+
+// WithSpanKind set span kind,eg: KindServer,KindClient
+func WithSpanKind(kind string) SpanOption {
+	return func(o *spanOption) {
+		o.kind = kind
+	}
+}
+
+// WithAttributes set span attributes,attrs is copied
+func WithAttributes(attrs map[string]interface{}) SpanOption {
+	return func(o *spanOption) {
+		if o.attrs == nil {
+			o.attrs = make(map[string]interface{}, len(attrs))
+		}
+
+		for k, v := range attrs {
+			o.attrs[k] = v
+		}
+	}
+}
+
+// WithNewRoot start a new trace ignoring the parent in context
+func WithNewRoot() SpanOption {
+	return func(o *spanOption) {
+		o.newRoot = true
+	}
+}
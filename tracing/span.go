@@ -0,0 +1,142 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-god/msa/logger"
+)
+
+// spanKey context key of span
+type spanKey struct{}
+
+// SpanData finished span data for exporters
+type SpanData struct {
+	ServiceName  string                 `json:"service_name,omitempty"`
+	Name         string                 `json:"name"`
+	Kind         string                 `json:"kind"`
+	TraceID      string                 `json:"trace_id"`
+	SpanID       string                 `json:"span_id"`
+	ParentSpanID string                 `json:"parent_span_id,omitempty"`
+	StartTime    time.Time              `json:"start_time"`
+	EndTime      time.Time              `json:"end_time"`
+	Duration     time.Duration          `json:"duration"`
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+	Error        string                 `json:"error,omitempty"`
+}
+
+// Span a unit of work in a trace
+type Span struct {
+	provider *Provider
+	sc       SpanContext
+	parent   SpanID
+	name     string
+	kind     string
+	start    time.Time
+
+	mu    sync.Mutex
+	attrs map[string]interface{}
+	err   error
+	ended bool
+}
+
+// SpanContext return span context
+func (s *Span) SpanContext() SpanContext {
+	return s.sc
+}
+
+// TraceID return hex trace id
+func (s *Span) TraceID() string {
+	return s.sc.TraceID.String()
+}
+
+// SpanID return hex span id
+func (s *Span) SpanID() string {
+	return s.sc.SpanID.String()
+}
+
+// SetAttribute set span attribute
+func (s *Span) SetAttribute(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.attrs == nil {
+		s.attrs = make(map[string]interface{}, 4)
+	}
+
+	s.attrs[key] = value
+}
+
+// SetError record error of span
+func (s *Span) SetError(err error) {
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+}
+
+// End finish span,if it is sampled it will be exported
+func (s *Span) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+
+	s.ended = true
+	end := time.Now()
+	data := &SpanData{
+		ServiceName: s.provider.conf.ServiceName,
+		Name:        s.name,
+		Kind:        s.kind,
+		TraceID:     s.TraceID(),
+		SpanID:      s.SpanID(),
+		StartTime:   s.start,
+		EndTime:     end,
+		Duration:    end.Sub(s.start),
+	}
+	// the exporter gets a snapshot,so the attributes set after End do not race with it
+	if len(s.attrs) > 0 {
+		data.Attributes = make(map[string]interface{}, len(s.attrs))
+		for k, v := range s.attrs {
+			data.Attributes[k] = v
+		}
+	}
+
+	if s.parent.IsValid() {
+		data.ParentSpanID = s.parent.String()
+	}
+
+	if s.err != nil {
+		data.Error = s.err.Error()
+	}
+	s.mu.Unlock()
+
+	if s.sc.Sampled {
+		s.provider.export(data)
+	}
+}
+
+// SpanFromContext return span of context,nil if it does not exist
+func SpanFromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanKey{}).(*Span)
+	return s
+}
+
+// SpanContextFromContext return span context of the span in context
+// or the remote span context extracted from request
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if s := SpanFromContext(ctx); s != nil {
+		return s.sc
+	}
+
+	sc, _ := ctx.Value(remoteKey{}).(SpanContext)
+	return sc
+}
+
+// ContextWithSpan return context with span,
+// the trace id and span id are also set as logger.TraceID and logger.SpanID
+func ContextWithSpan(ctx context.Context, s *Span) context.Context {
+	ctx = context.WithValue(ctx, spanKey{}, s)
+	ctx = context.WithValue(ctx, logger.TraceID, s.TraceID())
+	return context.WithValue(ctx, logger.SpanID, s.SpanID())
+}
@@ -0,0 +1,111 @@
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// TraceParentHeader w3c trace context header
+const TraceParentHeader = "traceparent"
+
+// ErrInvalidTraceParent invalid traceparent header
+var ErrInvalidTraceParent = errors.New("invalid traceparent")
+
+// TraceID w3c trace id
+type TraceID [16]byte
+
+// String return hex trace id
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// IsValid check trace id is not all zero
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+// SpanID w3c span id
+type SpanID [8]byte
+
+// String return hex span id
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// IsValid check span id is not all zero
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+// SpanContext identify a span across services
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+	Remote  bool // the span context is extracted from a remote parent
+}
+
+// IsValid check trace id and span id are valid
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// TraceParent return w3c traceparent header value,eg:
+// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func (sc SpanContext) TraceParent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+
+	return "00-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + flags
+}
+
+// ParseTraceParent parse w3c traceparent header value
+func ParseTraceParent(s string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" ||
+		len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return SpanContext{}, ErrInvalidTraceParent
+	}
+
+	var sc SpanContext
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil {
+		return SpanContext{}, ErrInvalidTraceParent
+	}
+
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil {
+		return SpanContext{}, ErrInvalidTraceParent
+	}
+
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil || !sc.IsValid() {
+		return SpanContext{}, ErrInvalidTraceParent
+	}
+
+	sc.Sampled = flags[0]&1 == 1
+	sc.Remote = true
+	return sc, nil
+}
+
+// newTraceID create a random trace id
+func newTraceID() TraceID {
+	var t TraceID
+	for !t.IsValid() {
+		_, _ = rand.Read(t[:])
+	}
+
+	return t
+}
+
+// newSpanID create a random span id
+func newSpanID() SpanID {
+	var s SpanID
+	for !s.IsValid() {
+		_, _ = rand.Read(s[:])
+	}
+
+	return s
+}
@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-god/msa/logger"
+)
+
+// TestTraceParent test parse and format traceparent.
+func TestTraceParent(t *testing.T) {
+	s := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	sc, err := ParseTraceParent(s)
+	if err != nil || !sc.Sampled || sc.TraceParent() != s {
+		t.Fatalf("span context: %+v error: %v", sc, err)
+	}
+
+	for _, invalid := range []string{"", "00-abc-def-01", "00-00000000000000000000000000000000-00f067aa0ba902b7-01"} {
+		if _, err = ParseTraceParent(invalid); err != ErrInvalidTraceParent {
+			t.Fatalf("parse %q error: %v", invalid, err)
+		}
+	}
+}
+
+// TestMiddleware test spans are propagated from request to client.
+func TestMiddleware(t *testing.T) {
+	exporter := NewMemoryExporter()
+	p := NewProvider(Config{ServiceName: "demo", SampleRatio: 1}, exporter)
+	if err := p.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer SetDefault(NewProvider(Config{SampleRatio: 1}, nil))
+
+	var logTraceID, outgoing string
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := Start(r.Context(), "query")
+		defer span.End()
+
+		logTraceID, _ = ctx.Value(logger.TraceID).(string)
+		header := http.Header{}
+		Inject(ctx, header)
+		outgoing = header.Get(TraceParentHeader)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set(TraceParentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if logTraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("logger trace id: %s", logTraceID)
+	}
+
+	spans := exporter.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("spans: %d", len(spans))
+	}
+
+	child, server := spans[0], spans[1]
+	if server.ParentSpanID != "00f067aa0ba902b7" || server.Kind != KindServer || child.ParentSpanID != server.SpanID {
+		t.Fatalf("server span: %+v child span: %+v", server, child)
+	}
+
+	if outgoing != "00-4bf92f3577b34da6a3ce929d0e0e4736-"+child.SpanID+"-01" {
+		t.Fatalf("outgoing traceparent: %s", outgoing)
+	}
+
+	if rec.Header().Get(TraceParentHeader) == "" {
+		t.Fatal("response traceparent is empty")
+	}
+}
+
+// TestSampleRatio test spans are not exported when they are not sampled.
+func TestSampleRatio(t *testing.T) {
+	exporter := NewMemoryExporter()
+	p := NewProvider(Config{SampleRatio: 0}, exporter)
+	ctx, span := p.Start(context.Background(), "root")
+	_, child := p.Start(ctx, "child")
+	child.End()
+	span.End()
+
+	if len(exporter.Spans()) != 0 || span.SpanContext().Sampled {
+		t.Fatalf("spans: %d", len(exporter.Spans()))
+	}
+}
+
+// TestSpanAttributes test the attributes are copied by WithAttributes and End.
+func TestSpanAttributes(t *testing.T) {
+	exporter := NewMemoryExporter()
+	p := NewProvider(Config{SampleRatio: 1}, exporter)
+	attrs := map[string]interface{}{"db": "mysql"}
+	_, span := p.Start(context.Background(), "query", WithAttributes(attrs))
+	attrs["db"] = "redis"
+	span.End()
+	span.SetAttribute("rows", 1)
+
+	spans := exporter.Spans()
+	if len(spans) != 1 || len(spans[0].Attributes) != 1 || spans[0].Attributes["db"] != "mysql" {
+		t.Fatalf("span attributes: %v", spans[0].Attributes)
+	}
+}
@@ -0,0 +1,143 @@
+package msa
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// DryRunFlag if os.Args contains this flag,the engine will run in dry-run mode
+var DryRunFlag = "--dry-run"
+
+// validator validate interface,config structs and inject objects can implement it
+type validator interface {
+	Validate() error
+}
+
+// confSection config section which is loaded before providers
+type confSection struct {
+	key string
+	obj interface{}
+}
+
+// ValidateError all problems found by Validate
+type ValidateError struct {
+	Errors []error
+}
+
+// Error implements error interface
+func (v *ValidateError) Error() string {
+	msgs := make([]string, 0, len(v.Errors))
+	for _, err := range v.Errors {
+		msgs = append(msgs, err.Error())
+	}
+
+	return fmt.Sprintf("%d problem(s) found: %s", len(v.Errors), strings.Join(msgs, "; "))
+}
+
+// Validate check config and inject wiring without running Init or Start.
+// It loads config sections, registers providers, runs injector Provide and Invoke,
+// validates config structs and inject objects which implement Validate() error,
+// and reports all problems at once by *ValidateError.
+// The invoke funcs added by WithInvoke are not called,only their parameter types are checked.
+// Validate uses a scratch injector,so the engine can still be started after it.
+func (e *Engine) Validate() error {
+	var errs []error
+	errs = append(errs, e.loadConfSections()...)
+	if err := catchPanic(e.loadProvides); err != nil {
+		errs = append(errs, fmt.Errorf("load providers error: %w", err))
+	}
+
+	nilObjects := 0
+	for i, obj := range e.injectValues {
+		if obj == nil || obj.Value == nil {
+			nilObjects++
+			errs = append(errs, fmt.Errorf("inject object #%d from %s is nil", i, e.objectSource(obj)))
+		}
+	}
+
+	// the injector can not provide nil objects
+	if nilObjects == 0 {
+		injector := e.newInjector()
+		if err := injector.Provide(e.injectValues...); err != nil {
+			errs = append(errs, fmt.Errorf("provide inject objects error: %w", err))
+		} else if err = injector.Invoke(e.checkInvokeFuncs); err != nil {
+			errs = append(errs, fmt.Errorf("inject invoke error: %w", err))
+		}
+	}
+
+	for _, obj := range e.injectValues {
+		if obj == nil {
+			continue
+		}
+
+		if v, ok := obj.Value.(validator); ok {
+			if err := v.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("validate %T error: %w", obj.Value, err))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		e.logMissingDeps()
+		return &ValidateError{Errors: errs}
+	}
+
+	return nil
+}
+
+// runDryRun validate engine and exit the process
+func (e *Engine) runDryRun() {
+	if err := e.Validate(); err != nil {
+		slog.Error("msa dry-run failed", "error", err)
+		e.closeLogger()
+		os.Exit(1)
+	}
+
+	slog.Info("msa dry-run successfully")
+	e.closeLogger()
+	os.Exit(0)
+}
+
+// loadConfSections load config sections and validate them
+func (e *Engine) loadConfSections() []error {
+	var errs []error
+	for _, section := range e.confSections {
+		if err := e.LoadConf(section.key, section.obj); err != nil {
+			errs = append(errs, fmt.Errorf("load config %s error: %w", section.key, err))
+			continue
+		}
+
+		if v, ok := section.obj.(validator); ok {
+			if err := v.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("validate config %s error: %w", section.key, err))
+			}
+		}
+	}
+
+	return errs
+}
+
+// hasDryRunFlag check os.Args contains DryRunFlag
+func hasDryRunFlag() bool {
+	for _, arg := range os.Args[1:] {
+		if arg == DryRunFlag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// catchPanic call fn and return the panic as an error
+func catchPanic(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	fn()
+	return nil
+}
@@ -0,0 +1,145 @@
+package msa
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-god/gdi"
+
+	"github.com/go-god/msa/config"
+)
+
+type validateConf struct {
+	Addr string
+}
+
+func (c *validateConf) Validate() error {
+	if c.Addr == "" {
+		return errors.New("addr is empty")
+	}
+
+	return nil
+}
+
+// TestEngineValidate test validate reports all problems.
+func TestEngineValidate(t *testing.T) {
+	e := &Engine{injector: defaultInjector()}
+	WithInjectValues(&gdi.Object{Value: &validateConf{}})(e)
+	WithInvoke(func(c *graphCache) {})(e)
+	err := e.Validate()
+	var vErr *ValidateError
+	if !errors.As(err, &vErr) || len(vErr.Errors) != 2 {
+		t.Fatalf("validate error: %v", err)
+	}
+}
+
+type validateProvider struct{}
+
+func (validateProvider) Provide() *gdi.Object {
+	return &gdi.Object{Value: &graphRepo{}}
+}
+
+// TestEngineValidateThenStart test the engine can be started after Validate.
+func TestEngineValidateThenStart(t *testing.T) {
+	e := &Engine{injector: defaultInjector()}
+	WithProviders(validateProvider{})(e)
+	WithInjectValues(&gdi.Object{Value: &graphService{}}, &gdi.Object{Value: &graphCache{}, Name: "cache"})(e)
+	calls := 0
+	WithInvoke(func(s *graphService) { calls++ })(e)
+	if err := e.Validate(); err != nil || calls != 0 {
+		t.Fatalf("validate error: %v invoke calls: %d", err, calls)
+	}
+
+	count := len(e.injectValues)
+	e.loadProvides()
+	if len(e.injectValues) != count {
+		t.Fatalf("providers registered again,inject values: %d want: %d", len(e.injectValues), count)
+	}
+
+	e.invokeInjects()
+	if calls != 1 {
+		t.Fatalf("invoke calls: %d", calls)
+	}
+}
+
+// flakyProvider panics at the first Provide
+type flakyProvider struct {
+	calls *int
+}
+
+func (p flakyProvider) Provide() *gdi.Object {
+	if *p.calls++; *p.calls == 1 {
+		panic("provider is not ready")
+	}
+
+	return &gdi.Object{Value: &graphCache{}, Name: "flaky"}
+}
+
+// TestEngineValidateRetryProviders test the providers are loaded again after a failed load.
+func TestEngineValidateRetryProviders(t *testing.T) {
+	e := &Engine{injector: defaultInjector()}
+	WithProviders(flakyProvider{calls: new(int)})(e)
+	if err := e.Validate(); err == nil {
+		t.Fatal("validate should fail when the provider panics")
+	}
+
+	if err := e.Validate(); err != nil {
+		t.Fatalf("validate again error: %v", err)
+	}
+
+	if _, ok := e.findObject("flaky", reflect.TypeOf(&graphCache{})); !ok {
+		t.Fatal("provider object not registered")
+	}
+}
+
+// TestEngineValidateNilObject test validate reports nil inject objects.
+func TestEngineValidateNilObject(t *testing.T) {
+	e := &Engine{injector: defaultInjector()}
+	WithInjectValues(&gdi.Object{Value: &graphRepo{}}, nil, &gdi.Object{})(e)
+	err := e.Validate()
+	var vErr *ValidateError
+	if !errors.As(err, &vErr) || len(vErr.Errors) != 2 {
+		t.Fatalf("validate error: %v", err)
+	}
+}
+
+// TestEngineDryRun test dry-run exits with the validate result.
+func TestEngineDryRun(t *testing.T) {
+	if mode := os.Getenv("MSA_DRY_RUN_TEST"); mode != "" {
+		dir := os.Getenv("MSA_DRY_RUN_DIR")
+		opts := []Option{WithDryRun(true), WithConfigInterface(config.New(config.WithConfigDir(dir),
+			config.WithConfigFile("app.yaml")))}
+		if mode == "fail" {
+			opts = append(opts, WithConfSection("app", &validateConf{}))
+		}
+
+		New(opts...).Start()
+		t.Fatal("dry-run did not exit")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.yaml"), []byte("app:\n  addr: \"\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for mode, wantCode := range map[string]int{"ok": 0, "fail": 1} {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestEngineDryRun$")
+		cmd.Env = append(os.Environ(), "MSA_DRY_RUN_TEST="+mode, "MSA_DRY_RUN_DIR="+dir)
+		err := cmd.Run()
+		code := 0
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			code = exitErr.ExitCode()
+		} else if err != nil {
+			t.Fatal(err)
+		}
+
+		if code != wantCode {
+			t.Fatalf("dry-run %s exit code: %d want: %d", mode, code, wantCode)
+		}
+	}
+}